@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	gm "chess-engine/goosemg"
+)
+
+// epdRecord is one parsed EPD line: the position plus its opcodes.
+type epdRecord struct {
+	Line int
+	FEN  string
+	Ops  map[string][]string
+}
+
+// loadEPD reads an EPD file, skipping blank lines and '#' comments.
+func loadEPD(path string) ([]epdRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []epdRecord
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rec, err := parseEPDLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+		rec.Line = lineNo
+		records = append(records, rec)
+	}
+	return records, scanner.Err()
+}
+
+// parseEPDLine splits an EPD line into its four FEN fields and the
+// semicolon-terminated operations that follow. Quoted operands may contain
+// spaces and semicolons.
+func parseEPDLine(line string) (epdRecord, error) {
+	rest := line
+	var fields []string
+	for i := 0; i < 4; i++ {
+		rest = strings.TrimLeft(rest, " \t")
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			end = len(rest)
+		}
+		if end == 0 {
+			return epdRecord{}, fmt.Errorf("expected 4 position fields")
+		}
+		fields = append(fields, rest[:end])
+		rest = rest[end:]
+	}
+
+	rec := epdRecord{Ops: make(map[string][]string)}
+	var tokens []string
+	var cur strings.Builder
+	inQuote := false
+	flushToken := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+	flushOp := func() {
+		flushToken()
+		if len(tokens) > 0 {
+			rec.Ops[tokens[0]] = tokens[1:]
+		}
+		tokens = nil
+	}
+	for _, ch := range rest {
+		switch {
+		case ch == '"':
+			inQuote = !inQuote
+		case inQuote:
+			cur.WriteRune(ch)
+		case ch == ';':
+			flushOp()
+		case ch == ' ' || ch == '\t':
+			flushToken()
+		default:
+			cur.WriteRune(ch)
+		}
+	}
+	if inQuote {
+		return epdRecord{}, fmt.Errorf("unterminated string operand")
+	}
+	flushOp()
+
+	halfmove, fullmove := "0", "1"
+	if v, ok := rec.Ops["hmvc"]; ok && len(v) == 1 {
+		halfmove = v[0]
+	}
+	if v, ok := rec.Ops["fmvn"]; ok && len(v) == 1 {
+		fullmove = v[0]
+	}
+	rec.FEN = strings.Join(fields, " ") + " " + halfmove + " " + fullmove
+	if _, err := gm.ParseFEN(rec.FEN); err != nil {
+		return epdRecord{}, err
+	}
+	return rec, nil
+}
+
+// id returns the "id" operand, falling back to the line number.
+func (r epdRecord) id() string {
+	if v := r.Ops["id"]; len(v) > 0 {
+		return strings.Join(v, " ")
+	}
+	return "line " + strconv.Itoa(r.Line)
+}
+
+// moves resolves the SAN operands of a move opcode (bm/am) to UCI strings.
+func (r epdRecord) moves(opcode string) (san []string, uci []string, err error) {
+	operands := r.Ops[opcode]
+	if len(operands) == 0 {
+		return nil, nil, nil
+	}
+	board := gm.ParseFen(r.FEN)
+	for _, s := range operands {
+		m, err := board.ParseSAN(s)
+		if err != nil {
+			// Some suites give coordinate moves instead of SAN.
+			m = findUCIMove(&board, s)
+			if m == 0 {
+				return nil, nil, fmt.Errorf("%s %s: %v", opcode, s, err)
+			}
+		}
+		san = append(san, board.SAN(m))
+		uci = append(uci, m.String())
+	}
+	return san, uci, nil
+}
+
+// mateDistance returns the "dm" operand, or 0 when absent.
+func (r epdRecord) mateDistance() (int, error) {
+	v := r.Ops["dm"]
+	if len(v) == 0 {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v[0])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid dm operand %q", v[0])
+	}
+	return n, nil
+}
+
+func findUCIMove(b *gm.Board, s string) gm.Move {
+	s = strings.ToLower(s)
+	for _, m := range b.GenerateLegalMoves() {
+		if m.String() == s {
+			return m
+		}
+	}
+	return 0
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// engineName returns the "id name" from the uci handshake reply, or "" when
+// the engine doesn't send one.
+func engineName(lines []string) string {
+	for _, line := range lines {
+		if name, ok := strings.CutPrefix(line, "id name "); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// searchInfo is the subset of an "info" line the runner cares about.
+type searchInfo struct {
+	Depth  int
+	TimeMs int64
+	Nodes  int64
+	Mate   int // signed mate distance in moves, 0 when the score is in centipawns
+	CP     int
+	PV     []string
+}
+
+// parseInfo parses "info depth D score cp X nodes N time T ... pv m1 m2 ...".
+// It reports false for info lines without a PV (e.g. "info string",
+// "info currmove") and for aspiration fail lines carrying a bound.
+func parseInfo(line string) (searchInfo, bool) {
+	tokens := strings.Fields(line)
+	if len(tokens) < 2 || tokens[0] != "info" {
+		return searchInfo{}, false
+	}
+	var info searchInfo
+	for i := 1; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "string", "lowerbound", "upperbound":
+			return searchInfo{}, false
+		case "depth":
+			if i+1 < len(tokens) {
+				info.Depth, _ = strconv.Atoi(tokens[i+1])
+				i++
+			}
+		case "time":
+			if i+1 < len(tokens) {
+				info.TimeMs, _ = strconv.ParseInt(tokens[i+1], 10, 64)
+				i++
+			}
+		case "nodes":
+			if i+1 < len(tokens) {
+				info.Nodes, _ = strconv.ParseInt(tokens[i+1], 10, 64)
+				i++
+			}
+		case "score":
+			if i+2 < len(tokens) {
+				v, _ := strconv.Atoi(tokens[i+2])
+				if tokens[i+1] == "mate" {
+					info.Mate = v
+				} else {
+					info.CP = v
+				}
+				i += 2
+			}
+		case "pv":
+			info.PV = tokens[i+1:]
+			i = len(tokens)
+		}
+	}
+	return info, len(info.PV) > 0
+}
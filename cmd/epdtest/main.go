@@ -0,0 +1,341 @@
+// cmd/epdtest/main.go
+//
+// EPD test-suite runner for tactical regression testing (WAC, STS, ...).
+// Each position is searched by a UCI engine binary under a fixed per-position
+// limit and scored against its bm/am/dm opcodes. The JSON report can be
+// compared against a report from another build with -compare.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"chess-engine/cmd/internal/uciclient"
+	gm "chess-engine/goosemg"
+)
+
+var (
+	enginePath  = flag.String("engine", "./gooseengine", "Path to the UCI engine binary")
+	epdPath     = flag.String("epd", "", "EPD suite to run (required)")
+	moveTime    = flag.Int("movetime", 1000, "Search time per position in ms (ignored when -depth or -nodes is set)")
+	depthFlag   = flag.Int("depth", 0, "Fixed search depth per position")
+	nodesFlag   = flag.Int("nodes", 0, "Fixed node budget per position; reproducible across runs and machines")
+	hashMB      = flag.Int("hash", 64, "Hash size in MB")
+	maxCount    = flag.Int("max", 0, "Run only the first N positions (0 = all)")
+	outPath     = flag.String("out", "", "Write the report to this file (default: stdout summary only)")
+	formatFlag  = flag.String("format", "json", `Report format: "json" or "csv"`)
+	comparePath = flag.String("compare", "", "JSON report from another build to diff against")
+	quiet       = flag.Bool("quiet", false, "Only print the summary")
+)
+
+// positionResult is the per-position entry of the report.
+type positionResult struct {
+	ID          string   `json:"id"`
+	FEN         string   `json:"fen"`
+	BM          []string `json:"bm,omitempty"`
+	AM          []string `json:"am,omitempty"`
+	DM          int      `json:"dm,omitempty"`
+	Comment     string   `json:"c0,omitempty"`
+	BestMove    string   `json:"best_move"`
+	Score       string   `json:"score"`
+	Depth       int      `json:"depth"`
+	Nodes       int64    `json:"nodes"`
+	TimeMs      int64    `json:"time_ms"`
+	Solved      bool     `json:"solved"`
+	SolveTimeMs int64    `json:"solve_time_ms"` // -1 when unsolved
+	SolveDepth  int      `json:"solve_depth"`
+}
+
+type report struct {
+	Engine           string           `json:"engine"`
+	Suite            string           `json:"suite"`
+	Limit            string           `json:"limit"`
+	Total            int              `json:"total"`
+	Solved           int              `json:"solved"`
+	SolvedPct        float64          `json:"solved_pct"`
+	TotalSolveTimeMs int64            `json:"total_solve_time_ms"`
+	Positions        []positionResult `json:"positions"`
+}
+
+func main() {
+	flag.Parse()
+	if *epdPath == "" {
+		fmt.Println("Usage: epdtest -epd <suite.epd> [-engine path] [-movetime ms | -depth N]")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	records, err := loadEPD(*epdPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "loading %s: %v\n", *epdPath, err)
+		os.Exit(2)
+	}
+	if *maxCount > 0 && len(records) > *maxCount {
+		records = records[:*maxCount]
+	}
+
+	goCmd := fmt.Sprintf("go movetime %d", *moveTime)
+	limit := fmt.Sprintf("movetime %d", *moveTime)
+	if *depthFlag > 0 || *nodesFlag > 0 {
+		goCmd, limit = "go", ""
+		if *depthFlag > 0 {
+			goCmd += fmt.Sprintf(" depth %d", *depthFlag)
+			limit = fmt.Sprintf("depth %d", *depthFlag)
+		}
+		if *nodesFlag > 0 {
+			goCmd += fmt.Sprintf(" nodes %d", *nodesFlag)
+			limit = strings.TrimSpace(limit + fmt.Sprintf(" nodes %d", *nodesFlag))
+		}
+	}
+
+	e, err := uciclient.Start(*enginePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "starting %s: %v\n", *enginePath, err)
+		os.Exit(1)
+	}
+	defer e.Close()
+	lines, err := e.Handshake(10 * time.Second)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "uci handshake: %v\n", err)
+		os.Exit(1)
+	}
+	_ = e.SetOption("Hash", *hashMB)
+
+	rep := report{Engine: engineName(lines), Suite: *epdPath, Limit: limit}
+	for _, rec := range records {
+		res, err := runPosition(e, rec, goCmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", rec.id(), err)
+			continue
+		}
+		rep.Positions = append(rep.Positions, res)
+		rep.Total++
+		if res.Solved {
+			rep.Solved++
+			rep.TotalSolveTimeMs += res.SolveTimeMs
+		}
+		if !*quiet {
+			status := "FAIL"
+			if res.Solved {
+				status = "ok  "
+			}
+			want := strings.Join(res.BM, " ")
+			if len(res.AM) > 0 {
+				want += " am " + strings.Join(res.AM, " ")
+			}
+			if res.DM > 0 {
+				want += fmt.Sprintf(" dm %d", res.DM)
+			}
+			fmt.Printf("%s %-20s played %-8s want %-16s depth %2d time %6d ms\n",
+				status, res.ID, res.BestMove, strings.TrimSpace(want), res.Depth, res.TimeMs)
+		}
+	}
+	if rep.Total > 0 {
+		rep.SolvedPct = 100 * float64(rep.Solved) / float64(rep.Total)
+	}
+	fmt.Printf("solved %d/%d (%.1f%%), total time-to-solution %d ms\n", rep.Solved, rep.Total, rep.SolvedPct, rep.TotalSolveTimeMs)
+
+	if *outPath != "" {
+		if err := writeReport(*outPath, *formatFlag, &rep); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if *comparePath != "" {
+		if err := compareReports(*comparePath, &rep); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// runPosition searches one EPD position and scores the result.
+func runPosition(e *uciclient.Engine, rec epdRecord, goCmd string) (positionResult, error) {
+	res := positionResult{ID: rec.id(), FEN: rec.FEN, SolveTimeMs: -1}
+	bmSAN, bmUCI, err := rec.moves("bm")
+	if err != nil {
+		return res, err
+	}
+	amSAN, amUCI, err := rec.moves("am")
+	if err != nil {
+		return res, err
+	}
+	if res.DM, err = rec.mateDistance(); err != nil {
+		return res, err
+	}
+	if len(bmUCI) == 0 && len(amUCI) == 0 && res.DM == 0 {
+		return res, fmt.Errorf("no bm, am or dm opcode")
+	}
+	res.BM, res.AM = bmSAN, amSAN
+	if c0 := rec.Ops["c0"]; len(c0) > 0 {
+		res.Comment = strings.Join(c0, " ")
+	}
+
+	solves := func(move string, mate int) bool {
+		if len(bmUCI) > 0 && !contains(bmUCI, move) {
+			return false
+		}
+		if len(amUCI) > 0 && contains(amUCI, move) {
+			return false
+		}
+		if res.DM > 0 && (mate <= 0 || mate > res.DM) {
+			return false
+		}
+		return true
+	}
+
+	if err := e.Send("ucinewgame"); err != nil {
+		return res, err
+	}
+	if err := e.IsReady(30 * time.Second); err != nil {
+		return res, err
+	}
+	if err := e.Send("position fen %s", rec.FEN); err != nil {
+		return res, err
+	}
+	start := time.Now()
+	if err := e.Send("%s", goCmd); err != nil {
+		return res, err
+	}
+	lines, err := e.ReadUntil("bestmove", 30*time.Minute)
+	if err != nil {
+		return res, err
+	}
+	res.TimeMs = time.Since(start).Milliseconds()
+
+	// Time-to-solution is the first iteration from which the PV move stayed
+	// a solution until the end of the search.
+	var last searchInfo
+	solvedSince := -1
+	var infos []searchInfo
+	for _, line := range lines {
+		info, ok := parseInfo(line)
+		if !ok {
+			continue
+		}
+		infos = append(infos, info)
+		last = info
+		if solves(info.PV[0], info.Mate) {
+			if solvedSince < 0 {
+				solvedSince = len(infos) - 1
+			}
+		} else {
+			solvedSince = -1
+		}
+	}
+
+	bestUCI := ""
+	if fields := strings.Fields(lines[len(lines)-1]); len(fields) >= 2 {
+		bestUCI = fields[1]
+	}
+	res.BestMove = bestUCI
+	board := gm.ParseFen(rec.FEN)
+	if m := findUCIMove(&board, bestUCI); m != 0 {
+		res.BestMove = board.SAN(m)
+	}
+	res.Depth = last.Depth
+	res.Nodes = last.Nodes
+	if last.Mate != 0 {
+		res.Score = "mate " + strconv.Itoa(last.Mate)
+	} else {
+		res.Score = "cp " + strconv.Itoa(last.CP)
+	}
+
+	res.Solved = solves(bestUCI, last.Mate)
+	if res.Solved {
+		if solvedSince >= 0 {
+			res.SolveTimeMs = infos[solvedSince].TimeMs
+			res.SolveDepth = infos[solvedSince].Depth
+		} else {
+			res.SolveTimeMs = res.TimeMs
+			res.SolveDepth = res.Depth
+		}
+	}
+	return res, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func writeReport(path string, format string, rep *report) error {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(rep, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, data, 0o644)
+	case "csv":
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w := csv.NewWriter(f)
+		_ = w.Write([]string{"id", "fen", "bm", "am", "dm", "best_move", "score", "depth", "nodes", "time_ms", "solved", "solve_time_ms", "solve_depth"})
+		for _, p := range rep.Positions {
+			_ = w.Write([]string{
+				p.ID, p.FEN, strings.Join(p.BM, " "), strings.Join(p.AM, " "), strconv.Itoa(p.DM),
+				p.BestMove, p.Score, strconv.Itoa(p.Depth), strconv.FormatInt(p.Nodes, 10),
+				strconv.FormatInt(p.TimeMs, 10), strconv.FormatBool(p.Solved),
+				strconv.FormatInt(p.SolveTimeMs, 10), strconv.Itoa(p.SolveDepth),
+			})
+		}
+		w.Flush()
+		return w.Error()
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+// compareReports prints positions whose solved status changed relative to a
+// baseline report, plus the change in time-to-solution on commonly solved ones.
+func compareReports(path string, cur *report) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var base report
+	if err := json.Unmarshal(data, &base); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	baseByKey := make(map[string]positionResult, len(base.Positions))
+	for _, p := range base.Positions {
+		baseByKey[p.ID+"|"+p.FEN] = p
+	}
+
+	var gained, lost int
+	var baseTime, curTime int64
+	for _, p := range cur.Positions {
+		b, ok := baseByKey[p.ID+"|"+p.FEN]
+		if !ok {
+			continue
+		}
+		switch {
+		case p.Solved && !b.Solved:
+			gained++
+			fmt.Printf("+ %-20s now solved (%s)\n", p.ID, p.BestMove)
+		case !p.Solved && b.Solved:
+			lost++
+			fmt.Printf("- %-20s no longer solved (played %s, was %s)\n", p.ID, p.BestMove, b.BestMove)
+		case p.Solved && b.Solved:
+			baseTime += b.SolveTimeMs
+			curTime += p.SolveTimeMs
+		}
+	}
+	fmt.Printf("compare vs %s: %d -> %d solved (+%d/-%d), time-to-solution on common solves %d -> %d ms\n",
+		path, base.Solved, cur.Solved, gained, lost, baseTime, curTime)
+	return nil
+}
@@ -64,6 +64,12 @@ type modelJSON struct {
 	QueenMobilityEG  []float64 `json:"queen_mobility_eg,omitempty"`
 	// Phase 4
 	KingSafety []float64 `json:"king_safety_table,omitempty"`
+	// Threats (per kind, engine ThreatByPawn order)
+	ThreatsMG []float64 `json:"threats_mg,omitempty"`
+	ThreatsEG []float64 `json:"threats_eg,omitempty"`
+	// Dynamic passed pawn terms (engine PassedFreePath order)
+	PassedTermsMG []float64 `json:"passed_terms_mg,omitempty"`
+	PassedTermsEG []float64 `json:"passed_terms_eg,omitempty"`
 	// Fixed-feature scalars (not in theta)
 	CandidatePassedPctMG *float64 `json:"candidate_passed_pct_mg,omitempty"`
 	CandidatePassedPctEG *float64 `json:"candidate_passed_pct_eg,omitempty"`
@@ -80,7 +86,7 @@ type modelJSON struct {
 	ImbalanceBishopPerPawnEG *float64 `json:"imbalance_bishop_per_pawn_eg,omitempty"`
 }
 
-// Theta layout (length 1217)
+// Theta layout (length 1241)
 // 0..383: PST MG (6x64)
 // 384..767: PST EG (6x64)
 // 768..773: Material MG (6)
@@ -89,17 +95,19 @@ type modelJSON struct {
 // 840..899: Mobility EG tables (60)
 // 900..903: Core scalars (4)
 // 904..912: Tier1 extras (9)
-// 913..976: Passers MG (64)
-// 977..1040: Passers EG (64)
-// 1041..1056: PawnStruct (16)
-// 1057..1156: KingSafetyTable (100)
-// 1157..1160: King-safety correlates (4)
-// 1161..1162: King endgame terms (2) (not exported)
-// 1163..1206: Tier3 extras (44)
-// 1207..1207: WeakKingSquares (1)
-// 1208..1209: BishopPair (2)
-// 1210..1213: Material imbalance scalars (4)
-// 1214..1216: Space/Tempo (3)
+// 913..924: Threats MG (6), Threats EG (6)
+// 925..988: Passers MG (64)
+// 989..1052: Passers EG (64)
+// 1053..1068: PawnStruct (16)
+// 1069..1080: Passed pawn terms MG (6), EG (6)
+// 1081..1180: KingSafetyTable (100)
+// 1181..1184: King-safety correlates (4)
+// 1185..1186: King endgame terms (2) (not exported)
+// 1187..1230: Tier3 extras (44)
+// 1231..1231: WeakKingSquares (1)
+// 1232..1233: BishopPair (2)
+// 1234..1237: Material imbalance scalars (4)
+// 1238..1240: Space/Tempo (3)
 
 func rd(x float64) int { return int(math.Round(x)) }
 
@@ -238,6 +246,10 @@ func main() {
 	var rMobEG [15]int
 	var qMobMG [22]int
 	var qMobEG [22]int
+	// Threats (pawn, minor, rook, hanging, pawn push, restricted)
+	var threatMG, threatEG [6]int
+	// Passed pawn terms (free path, stop defended, stop attacked, rook behind, connected, unstoppable)
+	var passTermMG, passTermEG [6]int
 	// King safety table + correlates
 	var ks [100]int
 	var kSemi, kOpen, kMinor, kPawn int
@@ -344,6 +356,16 @@ func main() {
 		}
 		idx += 9
 
+		// Threats MG/EG (6 + 6)
+		for i := 0; i < len(threatMG) && idx < len(th); i++ {
+			threatMG[i] = rd(th[idx])
+			idx++
+		}
+		for i := 0; i < len(threatEG) && idx < len(th); i++ {
+			threatEG[i] = rd(th[idx])
+			idx++
+		}
+
 		// Passers MG/EG
 		for i := 0; i < 64 && idx < len(th); i++ {
 			passMG[i] = rd(th[idx])
@@ -375,6 +397,16 @@ func main() {
 		}
 		idx += 16
 
+		// Passed pawn terms MG/EG (6 + 6)
+		for i := 0; i < len(passTermMG) && idx < len(th); i++ {
+			passTermMG[i] = rd(th[idx])
+			idx++
+		}
+		for i := 0; i < len(passTermEG) && idx < len(th); i++ {
+			passTermEG[i] = rd(th[idx])
+			idx++
+		}
+
 		// King safety table (100)
 		for i := 0; i < 100 && idx < len(th); i++ {
 			ks[i] = rd(th[idx])
@@ -575,6 +607,26 @@ func main() {
 				ks[i] = rd(m.KingSafety[i])
 			}
 		}
+		if len(m.ThreatsMG) == len(threatMG) {
+			for i := 0; i < len(threatMG); i++ {
+				threatMG[i] = rd(m.ThreatsMG[i])
+			}
+		}
+		if len(m.ThreatsEG) == len(threatEG) {
+			for i := 0; i < len(threatEG); i++ {
+				threatEG[i] = rd(m.ThreatsEG[i])
+			}
+		}
+		if len(m.PassedTermsMG) == len(passTermMG) {
+			for i := 0; i < len(passTermMG); i++ {
+				passTermMG[i] = rd(m.PassedTermsMG[i])
+			}
+		}
+		if len(m.PassedTermsEG) == len(passTermEG) {
+			for i := 0; i < len(passTermEG); i++ {
+				passTermEG[i] = rd(m.PassedTermsEG[i])
+			}
+		}
 		if m.CandidatePassedPctMG != nil {
 			candPctMG = rd(*m.CandidatePassedPctMG)
 		}
@@ -650,6 +702,10 @@ func main() {
 		out.WriteString(fmt.Sprintf("var RookMobilityEG = [15]int{%s}\n", formatArrayInline(rMobEG[:])))
 		out.WriteString(fmt.Sprintf("var QueenMobilityMG = [22]int{%s}\n", formatArrayInline(qMobMG[:])))
 		out.WriteString(fmt.Sprintf("var QueenMobilityEG = [22]int{%s}\n", formatArrayInline(qMobEG[:])))
+
+		// Threats
+		out.WriteString(fmt.Sprintf("var ThreatMG = [ThreatCount]int{%s}\n", formatArrayInline(threatMG[:])))
+		out.WriteString(fmt.Sprintf("var ThreatEG = [ThreatCount]int{%s}\n", formatArrayInline(threatEG[:])))
 	}
 
 	// PassedPawnPSQT (Tier 2)
@@ -661,6 +717,10 @@ func main() {
 		out.WriteString("var PassedPawnPSQT_EG = [64]int{\n")
 		out.WriteString(formatArray64(passEG))
 		out.WriteString("}\n")
+
+		// Dynamic passed pawn terms
+		out.WriteString(fmt.Sprintf("var PassedTermMG = [PassedTermCount]int{%s}\n", formatArrayInline(passTermMG[:])))
+		out.WriteString(fmt.Sprintf("var PassedTermEG = [PassedTermCount]int{%s}\n", formatArrayInline(passTermEG[:])))
 	}
 
 	// Scalar parameters in var() block - matching evaluation.go order
@@ -0,0 +1,124 @@
+// Package uciclient is a minimal UCI client around an engine subprocess,
+// shared by the command-line tools that drive the engine (spsa, epdtest).
+package uciclient
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Engine is a running UCI engine. Output lines are read in the background
+// and consumed with ReadUntil.
+type Engine struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	lines chan string
+}
+
+// Start launches the engine at path.
+func Start(path string) (*Engine, error) {
+	cmd := exec.Command(path)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	e := &Engine{cmd: cmd, stdin: stdin, lines: make(chan string, 256)}
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			e.lines <- scanner.Text()
+		}
+		close(e.lines)
+	}()
+	return e, nil
+}
+
+// Send writes one command line to the engine.
+func (e *Engine) Send(format string, args ...any) error {
+	_, err := fmt.Fprintf(e.stdin, format+"\n", args...)
+	return err
+}
+
+// ReadUntil collects output lines until one starts with prefix. The matching
+// line is the last element of the returned slice.
+func (e *Engine) ReadUntil(prefix string, timeout time.Duration) ([]string, error) {
+	var out []string
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		select {
+		case line, ok := <-e.lines:
+			if !ok {
+				return out, fmt.Errorf("engine exited while waiting for %q", prefix)
+			}
+			out = append(out, line)
+			if strings.HasPrefix(line, prefix) {
+				return out, nil
+			}
+		case <-deadline.C:
+			return out, fmt.Errorf("timeout waiting for %q", prefix)
+		}
+	}
+}
+
+// Handshake sends "uci" and returns the engine's reply up to "uciok" (the id
+// and option lines).
+func (e *Engine) Handshake(timeout time.Duration) ([]string, error) {
+	if err := e.Send("uci"); err != nil {
+		return nil, err
+	}
+	return e.ReadUntil("uciok", timeout)
+}
+
+// IsReady sends "isready" and waits for "readyok".
+func (e *Engine) IsReady(timeout time.Duration) error {
+	if err := e.Send("isready"); err != nil {
+		return err
+	}
+	_, err := e.ReadUntil("readyok", timeout)
+	return err
+}
+
+// Stop sends "stop" and waits for the bestmove that ends the search.
+func (e *Engine) Stop(timeout time.Duration) error {
+	if err := e.Send("stop"); err != nil {
+		return err
+	}
+	_, err := e.ReadUntil("bestmove", timeout)
+	return err
+}
+
+// SetOption sends "setoption name <name> value <value>".
+func (e *Engine) SetOption(name string, value any) error {
+	return e.Send("setoption name %s value %v", name, value)
+}
+
+// Close asks the engine to quit and kills it if it hasn't exited after two
+// seconds.
+func (e *Engine) Close() {
+	_ = e.Send("quit")
+	_ = e.stdin.Close()
+	done := make(chan struct{})
+	go func() {
+		_ = e.cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		_ = e.cmd.Process.Kill()
+	}
+}
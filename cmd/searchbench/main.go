@@ -18,6 +18,8 @@ func main() {
 	depthFlag := flag.Int("depth", 10, "search depth in plies")
 	repeatFlag := flag.Int("repeat", 1, "number of searches to run")
 	fenFlag := flag.String("fen", "", "FEN to search (empty = startpos)")
+	nodesFlag := flag.Int("nodes", 0, "stop each search after this many nodes (0 = no limit; makes runs reproducible)")
+	softNodesFlag := flag.Int("softnodes", 0, "start no new iteration after this many nodes (0 = no limit; makes runs reproducible)")
 	cpuProfile := flag.String("cpuprofile", "", "write CPU profile to file")
 	memProfile := flag.String("memprofile", "", "write memory profile (heap) to file")
 	flag.Parse()
@@ -60,7 +62,7 @@ func main() {
 	depth := *depthFlag
 	repeat := *repeatFlag
 
-	fmt.Printf("searchbench: fen=%q depth=%d repeat=%d\n", fen, depth, repeat)
+	fmt.Printf("searchbench: fen=%q depth=%d nodes=%d softnodes=%d repeat=%d\n", fen, depth, *nodesFlag, *softNodesFlag, repeat)
 
 	startAll := time.Now()
 	for i := 0; i < repeat; i++ {
@@ -71,6 +73,7 @@ func main() {
 		engine.SearchState.ResetForNewGame()
 		engine.SearchState.SyncPositionState(&board)
 		engine.SearchState.ClearStop()
+		engine.SearchState.SetNodeLimits(*softNodesFlag, *nodesFlag)
 
 		iterStart := time.Now()
 		bestMove := engine.StartSearch(
@@ -86,7 +89,7 @@ func main() {
 		)
 		iterElapsed := time.Since(iterStart)
 
-		fmt.Printf("iteration %d: bestmove %v  time=%v\n", i+1, bestMove, iterElapsed)
+		fmt.Printf("iteration %d: bestmove %v  nodes=%d  time=%v\n", i+1, bestMove, engine.GetNodeCount(), iterElapsed)
 	}
 	totalElapsed := time.Since(startAll)
 	fmt.Printf("total time: %v\n", totalElapsed)
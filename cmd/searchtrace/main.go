@@ -0,0 +1,169 @@
+// searchtrace records the search tree of one position as JSON lines, or
+// summarizes such a trace by depth.
+//
+//	searchtrace -fen "<fen>" -depth 10 -out trace.jsonl
+//	searchtrace -stats trace.jsonl
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"chess-engine/engine"
+	gm "chess-engine/goosemg"
+)
+
+func main() {
+	fenFlag := flag.String("fen", "", "FEN to search (empty = startpos)")
+	depthFlag := flag.Int("depth", 8, "search depth in plies")
+	capacityFlag := flag.Int("capacity", 1_000_000, "number of most recent nodes to keep")
+	outFlag := flag.String("out", "", "write the trace to this file (default stdout)")
+	statsFlag := flag.String("stats", "", "summarize an existing trace file instead of searching")
+	flag.Parse()
+
+	if *statsFlag != "" {
+		if err := printStats(*statsFlag); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *depthFlag <= 0 {
+		log.Fatalf("depth must be positive, got %d", *depthFlag)
+	}
+
+	fen := gm.Startpos
+	if *fenFlag != "" {
+		fen = *fenFlag
+	}
+	board := gm.ParseFen(fen)
+	engine.SearchState.ResetForNewGame()
+	engine.SearchState.SyncPositionState(&board)
+	engine.SearchState.ClearStop()
+
+	engine.EnableTrace(*capacityFlag)
+	bestMove := engine.StartSearch(&board, uint8(*depthFlag), 1000000, 0, 0, true, false, false, false)
+	engine.DisableTrace()
+
+	out := os.Stdout
+	if *outFlag != "" {
+		f, err := os.Create(*outFlag)
+		if err != nil {
+			log.Fatalf("could not create trace file: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := engine.WriteTrace(out); err != nil {
+		log.Fatalf("could not write trace: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "bestmove %s, %d nodes searched, %d recorded\n",
+		bestMove, engine.GetNodeCount(), len(engine.TraceEvents()))
+}
+
+// depthStats aggregates the alphabeta nodes of one depth.
+type depthStats struct {
+	nodes, failHighs, firstMoveCuts     int
+	ttCuts, rfp, nmp, razor, probcut    int
+	futility, lmp, see, researches, pvs int
+}
+
+func printStats(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	byDepth := make(map[int8]*depthStats)
+	var qsNodes, qsStandPat, qsFailHighs, qsFirstMoveCuts, qsSee, qsDelta int
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for dec.More() {
+		var ev engine.TraceEvent
+		if err := dec.Decode(&ev); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		if ev.Kind == engine.TraceQuiescence {
+			qsNodes++
+			qsSee += ev.SEE
+			qsDelta += ev.Delta
+			if ev.Prune == engine.PruneStandPat {
+				qsStandPat++
+			}
+			if ev.CutIndex >= 0 {
+				qsFailHighs++
+				if ev.CutIndex == 0 {
+					qsFirstMoveCuts++
+				}
+			}
+			continue
+		}
+
+		s := byDepth[ev.Depth]
+		if s == nil {
+			s = &depthStats{}
+			byDepth[ev.Depth] = s
+		}
+		s.nodes++
+		if ev.PV {
+			s.pvs++
+		}
+		switch ev.Prune {
+		case engine.PruneTT:
+			s.ttCuts++
+		case engine.PruneRFP:
+			s.rfp++
+		case engine.PruneNullMove:
+			s.nmp++
+		case engine.PruneRazoring:
+			s.razor++
+		case engine.PruneProbCut:
+			s.probcut++
+		}
+		s.futility += ev.Futility
+		s.lmp += ev.LateMove
+		s.see += ev.SEE
+		s.researches += ev.Researches
+		if ev.CutIndex >= 0 {
+			s.failHighs++
+			if ev.CutIndex == 0 {
+				s.firstMoveCuts++
+			}
+		}
+	}
+
+	depths := make([]int, 0, len(byDepth))
+	for d := range byDepth {
+		depths = append(depths, int(d))
+	}
+	sort.Ints(depths)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "depth\tnodes\tpv\tfail-high\tfirst-move%\ttt\trfp\tnmp\trazor\tprobcut\tfutility\tlmp\tsee\tresearch\t")
+	for _, d := range depths {
+		s := byDepth[int8(d)]
+		fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t\n",
+			d, s.nodes, s.pvs, s.failHighs, percent(s.firstMoveCuts, s.failHighs),
+			s.ttCuts, s.rfp, s.nmp, s.razor, s.probcut, s.futility, s.lmp, s.see, s.researches)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nquiescence: %d nodes, %d stand-pat cutoffs, %d fail-highs (%s on the first move), %d SEE and %d delta prunes\n",
+		qsNodes, qsStandPat, qsFailHighs, percent(qsFirstMoveCuts, qsFailHighs), qsSee, qsDelta)
+	return nil
+}
+
+func percent(part, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", 100*float64(part)/float64(total))
+}
@@ -0,0 +1,477 @@
+// cmd/spsa/main.go
+//
+// SPSA tuner for the search constants the engine exposes as UCI spin options.
+// Defaults and ranges are read from the engine's own uci handshake. By default
+// the options in searchParams are tuned; -params selects any other spin
+// options by name.
+//
+// Each iteration perturbs every tuned parameter by ±c_k (in units of its
+// range), plays game pairs between theta+ and theta- with colors swapped, and
+// steps theta along the estimated gradient with gain a_k:
+//
+//	a_k = a / (A + k + 1)^alpha
+//	c_k = c / (k + 1)^gamma
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"chess-engine/cmd/internal/uciclient"
+	gm "chess-engine/goosemg"
+)
+
+var (
+	enginePath  = flag.String("engine", "./gooseengine", "Path to the UCI engine binary")
+	paramsFlag  = flag.String("params", "", "Comma-separated spin option names to tune (default: the search constants in searchParams)")
+	iterations  = flag.Int("iterations", 200, "Number of SPSA iterations")
+	pairs       = flag.Int("pairs", 4, "Game pairs (color-swapped) per iteration")
+	concurrency = flag.Int("concurrency", 1, "Game pairs played in parallel")
+	tcFlag      = flag.String("tc", "8+0.08", "Time control as base+inc in seconds")
+	depthFlag   = flag.Int("depth", 0, "Fixed search depth per move (overrides -tc when > 0)")
+	bookPath    = flag.String("book", "", "FEN/EPD opening file (default: start position)")
+	maxPlies    = flag.Int("maxplies", 400, "Adjudicate a draw after this many plies (0 = off)")
+	hashMB      = flag.Int("hash", 16, "Hash size (MB) for each engine instance")
+	aFlag       = flag.Float64("a", 0.05, "Step-size numerator a (fraction of range)")
+	cFlag       = flag.Float64("c", 0.05, "Perturbation numerator c (fraction of range)")
+	bigAFlag    = flag.Float64("A", -1, "Stability constant A (default: 10% of iterations)")
+	alphaFlag   = flag.Float64("alpha", 0.602, "Step-size decay exponent")
+	gammaFlag   = flag.Float64("gamma", 0.101, "Perturbation decay exponent")
+	seedFlag    = flag.Int64("seed", 1, "Random seed for perturbations and opening selection")
+	outPrefix   = flag.String("out", "spsa", "Output prefix; writes <out>.csv and <out>.json")
+)
+
+const handshakeTimeout = 10 * time.Second
+
+// searchParams are the options tuned when -params is empty. Table sizes,
+// Contempt and the lazy eval margin are spin options too, but they trade
+// speed or playing style rather than strength and are left alone.
+var searchParams = []string{
+	"FutilityBase", "FutilityScale", "RFPScale", "RazoringScale",
+	"LMRDepthLimit", "NullMoveMinDepth", "NMMarginBase", "NMMarginDepth",
+	"LMPOffset", "LMRMoveLimit", "LMRHistoryBonus", "LMRHistoryMalus",
+	"QuiescenceSeeMargin", "ProbCutSeeMargin", "SeeCaptureMargin",
+	"DeltaMargin", "AspirationWindowSize",
+}
+
+// iterationRecord is one row of the parameter trajectory.
+type iterationRecord struct {
+	Iteration int                `json:"iteration"`
+	Ak        float64            `json:"a_k"`
+	Ck        float64            `json:"c_k"`
+	Wins      int                `json:"wins"`
+	Draws     int                `json:"draws"`
+	Losses    int                `json:"losses"`
+	Result    float64            `json:"result"`
+	Theta     map[string]float64 `json:"theta"`
+	Values    map[string]int     `json:"values"`
+}
+
+type runConfig struct {
+	Engine     string  `json:"engine"`
+	Iterations int     `json:"iterations"`
+	Pairs      int     `json:"pairs"`
+	TC         string  `json:"tc,omitempty"`
+	Depth      int     `json:"depth,omitempty"`
+	A          float64 `json:"a"`
+	C          float64 `json:"c"`
+	BigA       float64 `json:"A"`
+	Alpha      float64 `json:"alpha"`
+	Gamma      float64 `json:"gamma"`
+	Seed       int64   `json:"seed"`
+}
+
+type trajectory struct {
+	Config     runConfig         `json:"config"`
+	Params     []spinOption      `json:"params"`
+	Iterations []iterationRecord `json:"iterations"`
+}
+
+// worker owns one engine pair: plus plays theta+, minus plays theta-. The
+// values from the last configure call are kept to set up restarted engines.
+type worker struct {
+	path        string
+	plus, minus *uciclient.Engine
+	params      []spinOption
+	plusVals    []int
+	minusVals   []int
+}
+
+func main() {
+	flag.Parse()
+
+	tc := timeControl{depth: *depthFlag}
+	if tc.depth <= 0 {
+		var err error
+		tc, err = parseTimeControl(*tcFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+
+	openings := []string{gm.Startpos}
+	if *bookPath != "" {
+		var err error
+		openings, err = loadOpenings(*bookPath)
+		if err != nil || len(openings) == 0 {
+			fmt.Fprintf(os.Stderr, "loading openings from %s: %v\n", *bookPath, err)
+			os.Exit(2)
+		}
+	}
+
+	params, err := discoverParams(*enginePath, *paramsFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if len(params) == 0 {
+		fmt.Fprintln(os.Stderr, "no tunable spin options selected")
+		os.Exit(2)
+	}
+
+	bigA := *bigAFlag
+	if bigA < 0 {
+		bigA = float64(*iterations) / 10
+	}
+
+	workers := make([]*worker, max(1, *concurrency))
+	for i := range workers {
+		w, err := newWorker(*enginePath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer w.close()
+		workers[i] = w
+	}
+
+	csvFile, err := os.Create(*outPrefix + ".csv")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer csvFile.Close()
+	csvOut := csv.NewWriter(csvFile)
+	header := []string{"iteration", "a_k", "c_k", "wins", "draws", "losses", "result"}
+	for _, p := range params {
+		header = append(header, p.Name)
+	}
+	_ = csvOut.Write(header)
+
+	traj := trajectory{
+		Config: runConfig{
+			Engine: *enginePath, Iterations: *iterations, Pairs: *pairs,
+			A: *aFlag, C: *cFlag, BigA: bigA, Alpha: *alphaFlag, Gamma: *gammaFlag, Seed: *seedFlag,
+		},
+		Params: params,
+	}
+	if tc.depth > 0 {
+		traj.Config.Depth = tc.depth
+	} else {
+		traj.Config.TC = *tcFlag
+	}
+
+	// theta is kept normalized to [0,1] over each option's [min,max] range.
+	theta := make([]float64, len(params))
+	for i, p := range params {
+		theta[i] = normalize(p, float64(p.Default))
+	}
+
+	rng := rand.New(rand.NewSource(*seedFlag))
+	fmt.Printf("spsa: tuning %d parameters, %d iterations x %d pairs\n", len(params), *iterations, *pairs)
+
+	for k := 0; k < *iterations; k++ {
+		ak := *aFlag / math.Pow(bigA+float64(k)+1, *alphaFlag)
+		ck := *cFlag / math.Pow(float64(k)+1, *gammaFlag)
+
+		delta := make([]float64, len(params))
+		plusVals := make([]int, len(params))
+		minusVals := make([]int, len(params))
+		for i, p := range params {
+			delta[i] = 1
+			if rng.Intn(2) == 0 {
+				delta[i] = -1
+			}
+			plusVals[i] = denormalize(p, theta[i]+ck*delta[i])
+			minusVals[i] = denormalize(p, theta[i]-ck*delta[i])
+		}
+
+		jobs := make([]string, *pairs)
+		for i := range jobs {
+			jobs[i] = openings[rng.Intn(len(openings))]
+		}
+		wins, draws, losses, err := playIteration(workers, params, plusVals, minusVals, jobs, tc)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "iteration %d: %v\n", k+1, err)
+			os.Exit(1)
+		}
+
+		// Result from theta+'s perspective in [-1, 1].
+		games := wins + draws + losses
+		result := 0.0
+		if games > 0 {
+			result = float64(wins-losses) / float64(games)
+		}
+		for i := range theta {
+			theta[i] += ak * result / (2 * ck * delta[i])
+			theta[i] = math.Max(0, math.Min(1, theta[i]))
+		}
+
+		rec := iterationRecord{
+			Iteration: k + 1, Ak: ak, Ck: ck,
+			Wins: wins, Draws: draws, Losses: losses, Result: result,
+			Theta:  make(map[string]float64, len(params)),
+			Values: make(map[string]int, len(params)),
+		}
+		row := []string{
+			strconv.Itoa(k + 1),
+			strconv.FormatFloat(ak, 'g', 6, 64),
+			strconv.FormatFloat(ck, 'g', 6, 64),
+			strconv.Itoa(wins), strconv.Itoa(draws), strconv.Itoa(losses),
+			strconv.FormatFloat(result, 'f', 4, 64),
+		}
+		for i, p := range params {
+			v := float64(p.Min) + theta[i]*float64(p.Max-p.Min)
+			rec.Theta[p.Name] = v
+			rec.Values[p.Name] = denormalize(p, theta[i])
+			row = append(row, strconv.FormatFloat(v, 'f', 3, 64))
+		}
+		_ = csvOut.Write(row)
+		csvOut.Flush()
+
+		traj.Iterations = append(traj.Iterations, rec)
+		if err := writeJSON(*outPrefix+".json", &traj); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+
+		fmt.Printf("iter %4d  +%d =%d -%d  result=%+.3f  a_k=%.4f c_k=%.4f\n", k+1, wins, draws, losses, result, ak, ck)
+	}
+
+	fmt.Println("final values:")
+	for i, p := range params {
+		fmt.Printf("  %s = %d (default %d)\n", p.Name, denormalize(p, theta[i]), p.Default)
+	}
+}
+
+// discoverParams runs the uci handshake and selects the options to tune.
+func discoverParams(path string, selection string) ([]spinOption, error) {
+	e, err := uciclient.Start(path)
+	if err != nil {
+		return nil, fmt.Errorf("starting %s: %v", path, err)
+	}
+	defer e.Close()
+	opts, err := handshake(e)
+	if err != nil {
+		return nil, fmt.Errorf("uci handshake: %v", err)
+	}
+
+	var selected []spinOption
+	if selection == "" {
+		for _, name := range searchParams {
+			for _, o := range opts {
+				if strings.EqualFold(o.Name, name) && o.Min < o.Max {
+					selected = append(selected, o)
+					break
+				}
+			}
+		}
+		return selected, nil
+	}
+
+	for _, name := range strings.Split(selection, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, o := range opts {
+			if strings.EqualFold(o.Name, name) {
+				selected = append(selected, o)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("engine does not advertise a spin option %q", name)
+		}
+	}
+	return selected, nil
+}
+
+func newWorker(path string) (*worker, error) {
+	w := &worker{path: path}
+	var err error
+	if w.plus, err = startEngine(path); err != nil {
+		return nil, err
+	}
+	if w.minus, err = startEngine(path); err != nil {
+		w.plus.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// startEngine launches an engine and runs the uci handshake and Hash setup.
+func startEngine(path string) (*uciclient.Engine, error) {
+	e, err := uciclient.Start(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := e.Handshake(handshakeTimeout); err != nil {
+		e.Close()
+		return nil, err
+	}
+	if err := e.SetOption("Hash", *hashMB); err != nil {
+		e.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// restart replaces an unresponsive engine with a fresh one configured with
+// the current iteration's values.
+func (w *worker) restart(e *uciclient.Engine) error {
+	e.Close()
+	fresh, err := startEngine(w.path)
+	if err != nil {
+		return err
+	}
+	vals := w.minusVals
+	if e == w.plus {
+		w.plus = fresh
+		vals = w.plusVals
+	} else {
+		w.minus = fresh
+	}
+	for i, p := range w.params {
+		if err := fresh.SetOption(p.Name, vals[i]); err != nil {
+			return err
+		}
+	}
+	return fresh.IsReady(handshakeTimeout)
+}
+
+// play plays one game and restarts an engine that stopped responding, so the
+// game still counts and the worker can go on.
+func (w *worker) play(white, black *uciclient.Engine, fen string, tc timeControl) (float64, error) {
+	s, _, err := playGame(white, black, fen, tc, *maxPlies)
+	var lost *unresponsiveError
+	if errors.As(err, &lost) {
+		err = w.restart(lost.engine)
+	}
+	return s, err
+}
+
+func (w *worker) close() {
+	w.plus.Close()
+	w.minus.Close()
+}
+
+// playIteration plays every opening in jobs as a color-swapped pair and
+// returns wins/draws/losses from theta+'s perspective.
+func playIteration(workers []*worker, params []spinOption, plusVals, minusVals []int, jobs []string, tc timeControl) (int, int, int, error) {
+	var mu sync.Mutex
+	var wins, draws, losses int
+	var firstErr error
+
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for _, w := range workers {
+		wg.Add(1)
+		go func(w *worker) {
+			defer wg.Done()
+			err := w.configure(params, plusVals, minusVals)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+			// After an error the worker keeps draining the queue so the
+			// sender never blocks.
+			for fen := range queue {
+				if err != nil {
+					continue
+				}
+				var scores [2]float64
+				var s float64
+				s, err = w.play(w.plus, w.minus, fen, tc)
+				scores[0] = s
+				if err == nil {
+					s, err = w.play(w.minus, w.plus, fen, tc)
+					scores[1] = 1 - s
+				}
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else {
+					for _, sc := range scores {
+						switch sc {
+						case 1:
+							wins++
+						case 0:
+							losses++
+						default:
+							draws++
+						}
+					}
+				}
+				mu.Unlock()
+			}
+		}(w)
+	}
+	for _, fen := range jobs {
+		queue <- fen
+	}
+	close(queue)
+	wg.Wait()
+	return wins, draws, losses, firstErr
+}
+
+func (w *worker) configure(params []spinOption, plusVals, minusVals []int) error {
+	w.params, w.plusVals, w.minusVals = params, plusVals, minusVals
+	for i, p := range params {
+		if err := w.plus.SetOption(p.Name, plusVals[i]); err != nil {
+			return err
+		}
+		if err := w.minus.SetOption(p.Name, minusVals[i]); err != nil {
+			return err
+		}
+	}
+	if err := w.plus.IsReady(handshakeTimeout); err != nil {
+		return err
+	}
+	return w.minus.IsReady(handshakeTimeout)
+}
+
+func writeJSON(path string, traj *trajectory) error {
+	data, err := json.MarshalIndent(traj, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func normalize(p spinOption, v float64) float64 {
+	if p.Max == p.Min {
+		return 0
+	}
+	return (v - float64(p.Min)) / float64(p.Max-p.Min)
+}
+
+// denormalize maps a normalized theta back to an integer option value.
+func denormalize(p spinOption, t float64) int {
+	t = math.Max(0, math.Min(1, t))
+	return int(math.Round(float64(p.Min) + t*float64(p.Max-p.Min)))
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"chess-engine/cmd/internal/uciclient"
+	gm "chess-engine/goosemg"
+)
+
+// timeControl is either a base+increment clock (milliseconds) or a fixed depth.
+type timeControl struct {
+	baseMs int
+	incMs  int
+	depth  int
+}
+
+// parseTimeControl parses "base+inc" in seconds, e.g. "10+0.1".
+func parseTimeControl(s string) (timeControl, error) {
+	base, inc, _ := strings.Cut(s, "+")
+	b, err := strconv.ParseFloat(base, 64)
+	if err != nil || b <= 0 {
+		return timeControl{}, fmt.Errorf("invalid time control %q", s)
+	}
+	tc := timeControl{baseMs: int(b * 1000)}
+	if inc != "" {
+		i, err := strconv.ParseFloat(inc, 64)
+		if err != nil || i < 0 {
+			return timeControl{}, fmt.Errorf("invalid increment in %q", s)
+		}
+		tc.incMs = int(i * 1000)
+	}
+	return tc, nil
+}
+
+// loadOpenings reads one FEN or EPD per line. EPD opcodes are dropped and
+// missing move counters are filled in.
+func loadOpenings(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var fens []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		fen := strings.Join(fields[:4], " ")
+		if len(fields) >= 6 && isNumber(fields[4]) && isNumber(fields[5]) {
+			fen += " " + fields[4] + " " + fields[5]
+		} else {
+			fen += " 0 1"
+		}
+		if _, err := gm.ParseFEN(fen); err != nil {
+			return nil, fmt.Errorf("%s: %v", fen, err)
+		}
+		fens = append(fens, fen)
+	}
+	return fens, scanner.Err()
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+// stopTimeout is how long an engine that ran out of time gets to answer stop.
+const stopTimeout = 5 * time.Second
+
+// unresponsiveError reports an engine that answered neither go nor stop. The
+// game is still scored; the caller must replace the engine before reusing it.
+type unresponsiveError struct {
+	engine *uciclient.Engine
+	err    error
+}
+
+func (e *unresponsiveError) Error() string {
+	return fmt.Sprintf("engine not responding to stop: %v", e.err)
+}
+
+// playGame plays one game and returns White's score (1, 0.5 or 0) and the
+// reason the game ended.
+func playGame(white, black *uciclient.Engine, fen string, tc timeControl, maxPlies int) (float64, string, error) {
+	board := gm.ParseFen(fen)
+	history := []uint64{board.Hash()}
+	var moves []string
+	clocks := [2]int{tc.baseMs, tc.baseMs}
+
+	for _, e := range []*uciclient.Engine{white, black} {
+		if err := e.Send("ucinewgame"); err != nil {
+			return 0, "", err
+		}
+		if err := e.IsReady(10 * time.Second); err != nil {
+			return 0, "", err
+		}
+	}
+
+	for ply := 0; ; ply++ {
+		legal := board.GenerateLegalMoves()
+		if len(legal) == 0 {
+			if board.OurKingInCheck() {
+				if board.Wtomove {
+					return 0, "checkmate", nil
+				}
+				return 1, "checkmate", nil
+			}
+			return 0.5, "stalemate", nil
+		}
+		if board.IsDrawBy50() {
+			return 0.5, "fifty moves", nil
+		}
+		if board.IsDrawByRepetition(history) {
+			return 0.5, "repetition", nil
+		}
+		if insufficientMaterial(&board) {
+			return 0.5, "insufficient material", nil
+		}
+		if maxPlies > 0 && ply >= maxPlies {
+			return 0.5, "max plies", nil
+		}
+
+		side := 0
+		e := white
+		if !board.Wtomove {
+			side = 1
+			e = black
+		}
+		lossScore := 0.0
+		if side == 1 {
+			lossScore = 1.0
+		}
+
+		position := "position fen " + fen
+		if len(moves) > 0 {
+			position += " moves " + strings.Join(moves, " ")
+		}
+		if err := e.Send("%s", position); err != nil {
+			return 0, "", err
+		}
+		if tc.depth > 0 {
+			err := e.Send("go depth %d", tc.depth)
+			if err != nil {
+				return 0, "", err
+			}
+		} else {
+			err := e.Send("go wtime %d btime %d winc %d binc %d", clocks[0], clocks[1], tc.incMs, tc.incMs)
+			if err != nil {
+				return 0, "", err
+			}
+		}
+
+		start := time.Now()
+		timeout := 10 * time.Minute
+		if tc.depth == 0 {
+			timeout = time.Duration(clocks[side]+5000) * time.Millisecond
+		}
+		lines, err := e.ReadUntil("bestmove", timeout)
+		if err != nil {
+			// Stop the search so its late bestmove isn't read in the next
+			// game. An engine that doesn't answer has to be restarted.
+			if err := e.Stop(stopTimeout); err != nil {
+				return lossScore, "no bestmove", &unresponsiveError{engine: e, err: err}
+			}
+			return lossScore, "no bestmove", nil
+		}
+		if tc.depth == 0 {
+			clocks[side] -= int(time.Since(start).Milliseconds())
+			if clocks[side] < 0 {
+				return lossScore, "time forfeit", nil
+			}
+			clocks[side] += tc.incMs
+		}
+
+		fields := strings.Fields(lines[len(lines)-1])
+		if len(fields) < 2 {
+			return lossScore, "malformed bestmove", nil
+		}
+		var played gm.Move
+		for _, mv := range legal {
+			if mv.String() == fields[1] {
+				played = mv
+				break
+			}
+		}
+		if played == 0 {
+			return lossScore, "illegal move " + fields[1], nil
+		}
+
+		board.Apply(played)
+		moves = append(moves, fields[1])
+		history = append(history, board.Hash())
+	}
+}
+
+// insufficientMaterial reports bare kings or king and a single minor piece.
+func insufficientMaterial(b *gm.Board) bool {
+	if b.White.Pawns|b.Black.Pawns|b.White.Rooks|b.Black.Rooks|b.White.Queens|b.Black.Queens != 0 {
+		return false
+	}
+	minors := b.White.Knights | b.White.Bishops | b.Black.Knights | b.Black.Bishops
+	return bits.OnesCount64(minors) <= 1
+}
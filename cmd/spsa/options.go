@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"chess-engine/cmd/internal/uciclient"
+)
+
+// spinOption describes a "type spin" option advertised in the uci handshake.
+type spinOption struct {
+	Name    string `json:"name"`
+	Default int    `json:"default"`
+	Min     int    `json:"min"`
+	Max     int    `json:"max"`
+}
+
+// handshake runs the uci handshake and returns every spin option the engine
+// advertises.
+func handshake(e *uciclient.Engine) ([]spinOption, error) {
+	lines, err := e.Handshake(handshakeTimeout)
+	if err != nil {
+		return nil, err
+	}
+	var opts []spinOption
+	for _, line := range lines {
+		if opt, ok := parseSpinOption(line); ok {
+			opts = append(opts, opt)
+		}
+	}
+	return opts, nil
+}
+
+// parseSpinOption parses "option name <Name...> type spin default D min A max B".
+// Option names may contain spaces.
+func parseSpinOption(line string) (spinOption, bool) {
+	tokens := strings.Fields(line)
+	if len(tokens) < 4 || tokens[0] != "option" || tokens[1] != "name" {
+		return spinOption{}, false
+	}
+
+	typeIdx := -1
+	for i := 2; i < len(tokens); i++ {
+		if tokens[i] == "type" {
+			typeIdx = i
+			break
+		}
+	}
+	if typeIdx <= 2 || typeIdx+1 >= len(tokens) || tokens[typeIdx+1] != "spin" {
+		return spinOption{}, false
+	}
+
+	opt := spinOption{Name: strings.Join(tokens[2:typeIdx], " ")}
+	seen := 0
+	for i := typeIdx + 2; i+1 < len(tokens); i += 2 {
+		v, err := strconv.Atoi(tokens[i+1])
+		if err != nil {
+			return spinOption{}, false
+		}
+		switch tokens[i] {
+		case "default":
+			opt.Default = v
+			seen |= 1
+		case "min":
+			opt.Min = v
+			seen |= 2
+		case "max":
+			opt.Max = v
+			seen |= 4
+		}
+	}
+	return opt, seen == 7 && opt.Min <= opt.Max
+}
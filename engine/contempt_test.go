@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+func TestDrawScoreFollowsRootSide(t *testing.T) {
+	defer func(c int32, a bool) { Contempt, AnalyseMode = c, a }(Contempt, AnalyseMode)
+	s := &searchState{}
+	Contempt, AnalyseMode = 30, false
+
+	b := gm.ParseFen(gm.Startpos)
+	s.setContempt(&b)
+	if got := s.drawScore(&b); got != -30 {
+		t.Errorf("root side draw score %d, want -30", got)
+	}
+	b.Apply(b.GenerateMoves()[0])
+	if got := s.drawScore(&b); got != 30 {
+		t.Errorf("opponent draw score %d, want 30", got)
+	}
+	if s.ttKeySalt != 0 {
+		t.Errorf("white root salts TT keys")
+	}
+
+	// Black at the root flips the signs and keeps its own TT entries
+	s.setContempt(&b)
+	if got := s.drawScore(&b); got != -30 {
+		t.Errorf("black root draw score %d, want -30", got)
+	}
+	if s.ttKeySalt == 0 {
+		t.Errorf("black root shares TT keys with white root")
+	}
+
+	// Contempt halves towards the bare endgame: one knight is phase 1 of 24
+	knk := gm.ParseFen("8/8/4k3/8/8/3NK3/8/8 w - - 0 1")
+	s.setContempt(&knk)
+	if want := int32((30*1 + 15*23) / 24); s.drawScore(&knk) != -want {
+		t.Errorf("endgame draw score %d, want %d", s.drawScore(&knk), -want)
+	}
+
+	AnalyseMode = true
+	s.setContempt(&b)
+	if s.drawScore(&b) != DrawScore || s.ttKeySalt != 0 {
+		t.Errorf("analysis mode kept contempt %d (salt %x)", s.drawScore(&b), s.ttKeySalt)
+	}
+}
+
+// searchKNK searches a drawn KNK position from a new game with the given
+// contempt.
+func searchKNK(contempt int32) int32 {
+	Contempt = contempt
+	b := gm.ParseFen("8/8/4k3/8/8/3NK3/8/8 w - - 0 1")
+	SearchState.ResetForNewGame()
+	SearchState.SyncPositionState(&b)
+	StartSearch(&b, 8, 1000000, 0, 0, true, false, false, false)
+	return SearchState.prevSearchScore
+}
+
+func TestContemptShiftsDrawnSearch(t *testing.T) {
+	defer func(c int32) { Contempt = c }(Contempt)
+	neutral := searchKNK(0)
+	withContempt := searchKNK(30)
+	// Every leaf of KNK is a theoretical draw, so the whole tree shifts by
+	// the root side's draw score.
+	if want := neutral - (30*1+15*23)/24; withContempt != want {
+		t.Errorf("KNK with contempt scores %d, want %d (%d without)", withContempt, want, neutral)
+	}
+}
+
+func TestContemptStaysOutOfCorrectionHistory(t *testing.T) {
+	defer func(c int32) { Contempt = c }(Contempt)
+	defer CorrHistClear()
+	searchKNK(0)
+	pawn, nonPawn := SearchState.pawnCorrHist, SearchState.nonPawnCorrHist
+	if pawn == ([2][corrHistSize]int32{}) {
+		t.Fatalf("search left no correction history to compare")
+	}
+	// The same tree shifted by contempt must teach the same eval errors
+	searchKNK(30)
+	if SearchState.pawnCorrHist != pawn || SearchState.nonPawnCorrHist != nonPawn {
+		t.Errorf("contempt changed the correction history")
+	}
+}
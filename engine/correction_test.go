@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+func TestCorrHistConvergesAndClears(t *testing.T) {
+	defer CorrHistClear()
+	b := gm.ParseFen("r1bqkb1r/pppp1ppp/2n2n2/4p3/4P3/2N2N2/PPPP1PPP/R1BQKB1R w KQkq - 4 4")
+	if got := CorrHistApply(&b, 10); got != 10 {
+		t.Fatalf("empty tables corrected 10 to %d", got)
+	}
+	// Search keeps finding the position 40cp better than the eval
+	for i := 0; i < 200; i++ {
+		CorrHistUpdate(&b, 10, 50, 10)
+	}
+	if got := CorrHistApply(&b, 10); got < 45 || got > 50 {
+		t.Errorf("corrected eval %d, want close to 50", got)
+	}
+
+	// The other side to move has its own tables
+	black := gm.ParseFen("r1bqkb1r/pppp1ppp/2n2n2/4p3/4P3/2N2N2/PPPP1PPP/R1BQKB1R b KQkq - 4 4")
+	if got := CorrHistApply(&black, 10); got != 10 {
+		t.Errorf("black to move corrected 10 to %d", got)
+	}
+
+	// Large errors are capped
+	for i := 0; i < 200; i++ {
+		CorrHistUpdate(&b, 20, -3000, 0)
+	}
+	if got := CorrHistApply(&b, 0); got != -corrHistMax/corrHistGrain {
+		t.Errorf("capped correction %d, want %d", got, -corrHistMax/corrHistGrain)
+	}
+
+	CorrHistClear()
+	if got := CorrHistApply(&b, 10); got != 10 {
+		t.Errorf("cleared tables corrected 10 to %d", got)
+	}
+}
+
+// TestCorrHistClosesTheWholeError runs the loop the search runs on every
+// visit of a node: take the corrected eval, search, and feed the result back.
+// The corrected eval must reach the search score rather than stop halfway.
+func TestCorrHistClosesTheWholeError(t *testing.T) {
+	defer CorrHistClear()
+	b := gm.ParseFen("r1bqkb1r/pppp1ppp/2n2n2/4p3/4P3/2N2N2/PPPP1PPP/R1BQKB1R w KQkq - 4 4")
+	s := &searchState{}
+	const rawEval, searchScore = 10, 50
+	eval := s.nodeEval(&b, rawEval, false)
+	for i := 0; i < 200; i++ {
+		if eval > searchScore {
+			t.Fatalf("visit %d: corrected eval %d overshot the search score %d", i, eval, searchScore)
+		}
+		s.learnEvalError(&b, 10, searchScore, rawEval)
+		eval = s.nodeEval(&b, rawEval, false)
+	}
+	if eval < searchScore-5 {
+		t.Errorf("corrected eval %d, want close to %d", eval, searchScore)
+	}
+}
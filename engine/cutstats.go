@@ -10,10 +10,12 @@ type CutStatistics struct {
 	RazoringCutoffs   uint64
 	FutilityPrunes    uint64
 	LateMovePrunes    uint64
+	SeeCapturePrunes  uint64
 	BetaCutoffs       uint64
 	QStandPatCutoffs  uint64
 	QBetaCutoffs      uint64
 	ProbCutCutoffs    uint64
+	LazyEvals         uint64
 }
 
 // PrintCutStats controls whether the engine dumps the cut statistics once the
@@ -33,8 +35,14 @@ func dumpCutStats() {
 	fmt.Printf("info string   Razoring cutoffs: %d\n", cutStats.RazoringCutoffs)
 	fmt.Printf("info string   Futility prunes: %d\n", cutStats.FutilityPrunes)
 	fmt.Printf("info string   Late move prunes: %d\n", cutStats.LateMovePrunes)
+	fmt.Printf("info string   SEE capture prunes: %d\n", cutStats.SeeCapturePrunes)
 	fmt.Printf("info string   Beta cutoffs: %d\n", cutStats.BetaCutoffs)
 	fmt.Printf("info string   QStandPat cutoffs: %d\n", cutStats.QStandPatCutoffs)
 	fmt.Printf("info string   QBeta cutoffs: %d\n", cutStats.QBetaCutoffs)
 	fmt.Printf("info string   ProbCutCutoffs cutoffs: %d\n", cutStats.ProbCutCutoffs)
+	fmt.Printf("info string   Lazy evals: %d\n", cutStats.LazyEvals)
+
+	ttStats := SearchState.tt.Stats()
+	fmt.Printf("info string TT hashfull: %d/1000\n", ttStats.Hashfull)
+	fmt.Printf("info string Pawn hash: %d entries, hit rate %d/1000\n", ttStats.PawnHashEntries, ttStats.PawnHashHitRate)
 }
@@ -0,0 +1,428 @@
+package engine
+
+import (
+	"math/bits"
+	"strings"
+	"sync"
+
+	gm "chess-engine/goosemg"
+)
+
+// =============================================================================
+// ENDGAMES
+// =============================================================================
+// Material configurations with dedicated knowledge, in two flavours:
+//   - endgame evaluations replace the regular evaluation entirely. They are
+//     keyed by material signature (KPK, KBNK, ...) and registered for both
+//     colors of the stronger side.
+//   - scale factors shrink the endgame half of the regular evaluation in
+//     drawish configurations (wrong-colored bishop, opposite-colored bishops).
+//
+// Endgame functions score from the stronger side's point of view.
+
+const (
+	// KnownWin is the base score of a won endgame that isn't a mate yet
+	KnownWin int32 = 10000
+
+	ScaleNormal = 64
+	ScaleDraw   = 0
+)
+
+type endgameFunc func(b *gm.Board, strong gm.Color) int32
+
+type endgameEntry struct {
+	name   string
+	eval   endgameFunc
+	strong gm.Color
+}
+
+var endgames = map[uint64]*endgameEntry{}
+
+func init() {
+	registerEndgame("KPK", evaluateKPK)
+	registerEndgame("KBNK", evaluateKBNK)
+	registerEndgame("KRKP", evaluateKRKP)
+	registerEndgame("KQKP", evaluateKQKP)
+}
+
+// registerEndgame adds f for the signature code, e.g. "KBNK", with either
+// color as the stronger side.
+func registerEndgame(code string, f endgameFunc) {
+	split := strings.LastIndexByte(code, 'K')
+	strong, weak := code[:split], code[split:]
+	endgames[signatureKey(strong, weak)] = &endgameEntry{code, f, gm.White}
+	endgames[signatureKey(weak, strong)] = &endgameEntry{code, f, gm.Black}
+}
+
+// materialKey packs both sides' piece counts, 4 bits per piece type.
+func materialKey(b *gm.Board) uint64 {
+	return sideMaterialKey(&b.White) | sideMaterialKey(&b.Black)<<20
+}
+
+func sideMaterialKey(bb *gm.Bitboards) uint64 {
+	return uint64(bits.OnesCount64(bb.Pawns)) |
+		uint64(bits.OnesCount64(bb.Knights))<<4 |
+		uint64(bits.OnesCount64(bb.Bishops))<<8 |
+		uint64(bits.OnesCount64(bb.Rooks))<<12 |
+		uint64(bits.OnesCount64(bb.Queens))<<16
+}
+
+// signatureKey is the materialKey of a position with the given white and
+// black pieces, e.g. signatureKey("KBN", "K").
+func signatureKey(white, black string) uint64 {
+	count := func(pieces string) uint64 {
+		var key uint64
+		for _, c := range pieces {
+			switch c {
+			case 'P':
+				key += 1
+			case 'N':
+				key += 1 << 4
+			case 'B':
+				key += 1 << 8
+			case 'R':
+				key += 1 << 12
+			case 'Q':
+				key += 1 << 16
+			}
+		}
+		return key
+	}
+	return count(white) | count(black)<<20
+}
+
+// probeEndgame returns the dedicated evaluation for b's material, nil if
+// there is none. The material hash caches the result.
+func probeEndgame(b *gm.Board) *endgameEntry {
+	// Every registered endgame has at most four men
+	if bits.OnesCount64(b.White.All|b.Black.All) > 4 {
+		return nil
+	}
+	return endgames[materialKey(b)]
+}
+
+// evaluateEndgame scores b from white's point of view when its material has
+// a dedicated evaluation.
+func evaluateEndgame(b *gm.Board) (score int32, name string, ok bool) {
+	e := GetMaterialEntry(b).endgame
+	if e == nil {
+		return 0, "", false
+	}
+	score = e.eval(b, e.strong)
+	if e.strong == gm.Black {
+		score = -score
+	}
+	return score, e.name, true
+}
+
+/* ============= HELPERS ============= */
+
+func sideBitboards(b *gm.Board, c gm.Color) *gm.Bitboards {
+	if c == gm.White {
+		return &b.White
+	}
+	return &b.Black
+}
+
+// relativeSquare flips sq vertically for black, so that the stronger side
+// always plays up the board.
+func relativeSquare(c gm.Color, sq int) int {
+	if c == gm.Black {
+		return sq ^ 56
+	}
+	return sq
+}
+
+func isDark(sq int) bool {
+	return darkSquares&(uint64(1)<<sq) != 0
+}
+
+func kingAttacksFrom(sq int) (attacks uint64) {
+	file, rank := sq&7, sq>>3
+	for df := -1; df <= 1; df++ {
+		for dr := -1; dr <= 1; dr++ {
+			f, r := file+df, rank+dr
+			if (df != 0 || dr != 0) && f >= 0 && f < 8 && r >= 0 && r < 8 {
+				attacks |= uint64(1) << (r*8 + f)
+			}
+		}
+	}
+	return attacks
+}
+
+func whitePawnAttacksFrom(sq int) (attacks uint64) {
+	if sq >= 56 {
+		return 0
+	}
+	if sq&7 > 0 {
+		attacks |= uint64(1) << (sq + 7)
+	}
+	if sq&7 < 7 {
+		attacks |= uint64(1) << (sq + 9)
+	}
+	return attacks
+}
+
+/* ============= KPK ============= */
+// A bitbase of all king and pawn versus king positions with the pawn on files
+// a-d, white to win, generated by retrograde analysis on first use.
+
+const kpkSize = 2 * 24 * 64 * 64
+
+var kpkBitbase [kpkSize / 64]uint64
+var kpkOnce sync.Once
+
+const (
+	kpkInvalid uint8 = 0
+	kpkUnknown uint8 = 1
+	kpkDraw    uint8 = 2
+	kpkWin     uint8 = 4
+)
+
+// kpkIndex encodes a position; us is 0 when the side with the pawn is to move.
+func kpkIndex(us, strongKing, weakKing, pawn int) int {
+	return strongKing | weakKing<<6 | us<<12 | (pawn&7)<<13 | (6-pawn>>3)<<15
+}
+
+// kpkProbe reports whether the pawn wins. Squares are from the pawn side's
+// point of view with the pawn on files a-d.
+func kpkProbe(strongToMove bool, strongKing, weakKing, pawn int) bool {
+	kpkOnce.Do(initKPK)
+	us := 1
+	if strongToMove {
+		us = 0
+	}
+	idx := kpkIndex(us, strongKing, weakKing, pawn)
+	return kpkBitbase[idx/64]&(uint64(1)<<(idx%64)) != 0
+}
+
+func initKPK() {
+	db := make([]uint8, kpkSize)
+	for idx := range db {
+		db[idx] = kpkInitial(idx)
+	}
+	for changed := true; changed; {
+		changed = false
+		for idx := range db {
+			if db[idx] != kpkUnknown {
+				continue
+			}
+			if r := kpkClassify(db, idx); r != kpkUnknown {
+				db[idx] = r
+				changed = true
+			}
+		}
+	}
+	for idx, r := range db {
+		if r == kpkWin {
+			kpkBitbase[idx/64] |= uint64(1) << (idx % 64)
+		}
+	}
+}
+
+func kpkDecode(idx int) (us, strongKing, weakKing, pawn int) {
+	return idx >> 12 & 1, idx & 63, idx >> 6 & 63, (idx>>13)&3 + 8*(6-idx>>15)
+}
+
+func kpkInitial(idx int) uint8 {
+	us, sk, wk, psq := kpkDecode(idx)
+	switch {
+	case kingDist(sk, wk) <= 1 || sk == psq || wk == psq:
+		return kpkInvalid
+	case us == 0 && whitePawnAttacksFrom(psq)&(uint64(1)<<wk) != 0:
+		return kpkInvalid
+	case us == 0 && psq>>3 == 6 && sk != psq+8 && (kingDist(wk, psq+8) > 1 || kingDist(sk, psq+8) == 1):
+		// The pawn promotes safely
+		return kpkWin
+	case us == 1 && kingAttacksFrom(wk)&^(kingAttacksFrom(sk)|whitePawnAttacksFrom(psq)) == 0:
+		// Stalemate
+		return kpkDraw
+	case us == 1 && kingDist(wk, psq) == 1 && kingDist(sk, psq) > 1:
+		// The pawn is lost
+		return kpkDraw
+	}
+	return kpkUnknown
+}
+
+// kpkClassify derives a result from the positions reachable in one move.
+func kpkClassify(db []uint8, idx int) uint8 {
+	us, sk, wk, psq := kpkDecode(idx)
+	var r uint8
+	if us == 0 {
+		for x := kingAttacksFrom(sk); x != 0; x &= x - 1 {
+			r |= db[kpkIndex(1, bits.TrailingZeros64(x), wk, psq)]
+		}
+		if psq>>3 < 6 {
+			push := psq + 8
+			r |= db[kpkIndex(1, sk, wk, push)]
+			if psq>>3 == 1 && push != sk && push != wk {
+				r |= db[kpkIndex(1, sk, wk, push+8)]
+			}
+		}
+		if r&kpkWin != 0 {
+			return kpkWin
+		}
+		if r&kpkUnknown != 0 {
+			return kpkUnknown
+		}
+		return kpkDraw
+	}
+
+	for x := kingAttacksFrom(wk); x != 0; x &= x - 1 {
+		r |= db[kpkIndex(0, sk, bits.TrailingZeros64(x), psq)]
+	}
+	if r&kpkDraw != 0 {
+		return kpkDraw
+	}
+	if r&kpkUnknown != 0 {
+		return kpkUnknown
+	}
+	return kpkWin
+}
+
+func evaluateKPK(b *gm.Board, strong gm.Color) int32 {
+	s, w := sideBitboards(b, strong), sideBitboards(b, strong^1)
+	sk := relativeSquare(strong, bits.TrailingZeros64(s.Kings))
+	wk := relativeSquare(strong, bits.TrailingZeros64(w.Kings))
+	psq := relativeSquare(strong, bits.TrailingZeros64(s.Pawns))
+	if psq&7 >= 4 {
+		sk, wk, psq = sk^7, wk^7, psq^7
+	}
+	strongToMove := b.Wtomove == (strong == gm.White)
+	if !kpkProbe(strongToMove, sk, wk, psq) {
+		return 0
+	}
+	return KnownWin + int32(pieceValueEG[gm.PieceTypePawn]) + int32(psq>>3)
+}
+
+/* ============= KBNK ============= */
+
+// evaluateKBNK drives the defending king to a corner of the bishop's color,
+// the only corners where the mate can be forced.
+func evaluateKBNK(b *gm.Board, strong gm.Color) int32 {
+	s, w := sideBitboards(b, strong), sideBitboards(b, strong^1)
+	sk := bits.TrailingZeros64(s.Kings)
+	wk := bits.TrailingZeros64(w.Kings)
+
+	cornerA, cornerB := 0, 63 // a1 and h8 are dark
+	if !isDark(bits.TrailingZeros64(s.Bishops)) {
+		cornerA, cornerB = 7, 56
+	}
+	cornerDist := min(manhattanDistance(wk, cornerA), manhattanDistance(wk, cornerB))
+
+	score := KnownWin + int32(pieceValueEG[gm.PieceTypeKnight]+pieceValueEG[gm.PieceTypeBishop])
+	score += int32((14-cornerDist)*20 + (7-kingDist(sk, wk))*10)
+	return score
+}
+
+func manhattanDistance(a, b int) int {
+	return absInt(a&7-b&7) + absInt(a>>3-b>>3)
+}
+
+/* ============= KRKP ============= */
+
+// evaluateKRKP is won when the stronger king blocks the pawn or the pawn is
+// too far from its king; it gets close to a draw when the pawn is advanced,
+// supported and the stronger king is out of play.
+func evaluateKRKP(b *gm.Board, strong gm.Color) int32 {
+	s, w := sideBitboards(b, strong), sideBitboards(b, strong^1)
+	// Seen from the stronger side, the pawn runs down the board
+	sk := relativeSquare(strong, bits.TrailingZeros64(s.Kings))
+	wk := relativeSquare(strong, bits.TrailingZeros64(w.Kings))
+	rsq := relativeSquare(strong, bits.TrailingZeros64(s.Rooks))
+	psq := relativeSquare(strong, bits.TrailingZeros64(w.Pawns))
+	queening := psq & 7
+	strongToMove := b.Wtomove == (strong == gm.White)
+
+	rookValue := pieceValueEG[gm.PieceTypeRook]
+	var result int
+	switch {
+	case sk&7 == psq&7 && sk < psq:
+		// The king stands in front of the pawn
+		result = rookValue - kingDist(sk, psq)
+	case kingDist(wk, psq) >= 3+btoi(!strongToMove) && kingDist(wk, rsq) >= 3:
+		// The pawn is left without its king
+		result = rookValue - kingDist(sk, psq)
+	case wk>>3 <= 2 && kingDist(wk, psq) == 1 && sk>>3 >= 3 && kingDist(sk, psq) > 2+btoi(strongToMove):
+		// Advanced and supported pawn, stronger king far away
+		result = 80 - 8*kingDist(sk, psq)
+	default:
+		result = 200 - 8*(kingDist(sk, psq-8)-kingDist(wk, psq-8)-kingDist(psq, queening))
+	}
+	return int32(result)
+}
+
+func btoi(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+/* ============= KQKP ============= */
+
+var pushClose = [8]int{0, 0, 100, 80, 60, 40, 20, 10}
+
+// evaluateKQKP is a win unless a rook or bishop pawn on its seventh rank is
+// supported by its king, which holds the draw.
+func evaluateKQKP(b *gm.Board, strong gm.Color) int32 {
+	s, w := sideBitboards(b, strong), sideBitboards(b, strong^1)
+	sk := bits.TrailingZeros64(s.Kings)
+	wk := bits.TrailingZeros64(w.Kings)
+	psq := relativeSquare(strong, bits.TrailingZeros64(w.Pawns))
+
+	result := pushClose[kingDist(sk, wk)]
+	file := psq & 7
+	drawishFile := file == 0 || file == 2 || file == 5 || file == 7
+	if psq>>3 != 1 || kingDist(relativeSquare(strong, wk), psq) != 1 || !drawishFile {
+		result += pieceValueEG[gm.PieceTypeQueen] - pieceValueEG[gm.PieceTypePawn]
+	}
+	return int32(result)
+}
+
+/* ============= SCALE FACTORS ============= */
+
+// endgameScale returns the factor, out of ScaleNormal, applied to the endgame
+// score egScore (white's point of view) of b. The material entry tells which
+// of the scale factors can apply at all.
+func endgameScale(b *gm.Board, mat *MaterialHashEntry, entry *PawnHashEntry, egScore int) int {
+	if egScore == 0 || mat.scaleFlags == 0 {
+		return ScaleNormal
+	}
+	strong := gm.White
+	passers := entry.WPassedBB
+	if egScore < 0 {
+		strong = gm.Black
+		passers = entry.BPassedBB
+	}
+	s, w := sideBitboards(b, strong), sideBitboards(b, strong^1)
+	sPieces := s.Knights | s.Bishops | s.Rooks | s.Queens
+	wPieces := w.Knights | w.Bishops | w.Rooks | w.Queens
+
+	// Rook pawns with a bishop that doesn't control the queening square can't
+	// win against a king that reaches the corner.
+	wrongBishop := scaleWrongBishopWhite
+	if strong == gm.Black {
+		wrongBishop = scaleWrongBishopBlack
+	}
+	if mat.scaleFlags&wrongBishop != 0 {
+		if s.Pawns&^bitboardFileA == 0 || s.Pawns&^bitboardFileH == 0 {
+			queening := relativeSquare(strong, 56+bits.TrailingZeros64(s.Pawns)&7)
+			bishopSq := bits.TrailingZeros64(s.Bishops)
+			if isDark(bishopSq) != isDark(queening) && kingDist(bits.TrailingZeros64(w.Kings), queening) <= 1 {
+				return ScaleDraw
+			}
+		}
+	}
+
+	// Opposite-colored bishops
+	if mat.scaleFlags&scaleOppositeBishops != 0 &&
+		isDark(bits.TrailingZeros64(s.Bishops)) != isDark(bits.TrailingZeros64(w.Bishops)) {
+		if sPieces == s.Bishops && wPieces == w.Bishops {
+			return min(ScaleNormal, 18+4*bits.OnesCount64(passers))
+		}
+		return min(ScaleNormal, 22+3*bits.OnesCount64(s.All))
+	}
+
+	return ScaleNormal
+}
@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+// evalWhite returns the evaluation of fen from white's point of view.
+func evalWhite(fen string) int32 {
+	b := gm.ParseFen(fen)
+	score := Evaluation(&b)
+	if !b.Wtomove {
+		score = -score
+	}
+	return score
+}
+
+func TestKPKBitbase(t *testing.T) {
+	initVariables(nil)
+	cases := []struct {
+		fen string
+		win bool
+	}{
+		{"4k3/8/4K3/4P3/8/8/8/8 w - - 0 1", true},  // king on the sixth in front of the pawn
+		{"4k3/8/4K3/4P3/8/8/8/8 b - - 0 1", true},  // ... whoever moves
+		{"4k3/8/4P3/4K3/8/8/8/8 w - - 0 1", false}, // king behind the pawn on the sixth
+		{"4k3/8/4P3/4K3/8/8/8/8 b - - 0 1", false},
+		{"4k3/8/8/4K3/8/4P3/8/8 b - - 0 1", true},  // king two squares ahead of the pawn
+		{"k7/8/K7/P7/8/8/8/8 w - - 0 1", false},    // rook pawn
+		{"7k/8/7K/7P/8/8/8/8 b - - 0 1", false},    // rook pawn, mirrored
+		{"8/8/8/8/6P1/8/8/k5K1 b - - 0 1", true},   // the pawn outruns the king
+		{"8/8/8/8/4k3/4p3/8/4K3 b - - 0 1", false}, // black pawn, king behind it
+		{"4K3/8/4k3/4p3/8/8/8/8 b - - 0 1", true},  // black king on its sixth
+		{"4K3/8/4k3/4p3/8/8/8/8 w - - 0 1", true},
+	}
+	for _, c := range cases {
+		b := gm.ParseFen(c.fen)
+		score, name, ok := evaluateEndgame(&b)
+		if !ok || name != "KPK" {
+			t.Errorf("%s: not recognized as KPK", c.fen)
+			continue
+		}
+		if won := score >= KnownWin || score <= -KnownWin; won != c.win {
+			t.Errorf("%s: score %d, want win=%v", c.fen, score, c.win)
+		}
+	}
+}
+
+func TestKBNKDrivesToBishopCorner(t *testing.T) {
+	initVariables(nil)
+	// Dark-squared bishop: a1 is a mating corner, h1 isn't
+	right := evalWhite("8/8/8/8/8/3NK3/8/k1B5 b - - 0 1")
+	wrong := evalWhite("8/8/8/8/8/3NK3/8/2B4k b - - 0 1")
+	if right < KnownWin || wrong < KnownWin {
+		t.Fatalf("KBNK not scored as a win: %d, %d", right, wrong)
+	}
+	if right <= wrong {
+		t.Errorf("king in the bishop's corner %d, in the wrong corner %d", right, wrong)
+	}
+}
+
+func TestKRKP(t *testing.T) {
+	initVariables(nil)
+	// The white king blocks the pawn
+	won := evalWhite("k7/8/8/8/3p4/8/3K4/7R w - - 0 1")
+	// Advanced pawn next to its king, white king far away
+	drawish := evalWhite("7K/8/8/8/8/8/2kp4/7R w - - 0 1")
+	if won < 400 {
+		t.Errorf("blocked pawn: %d, want a rook up", won)
+	}
+	if drawish > 100 || drawish < 0 {
+		t.Errorf("supported pawn on the seventh: %d, want close to a draw", drawish)
+	}
+	// Same with colors reversed
+	if got := evalWhite("7r/3k4/8/3P4/8/8/8/K7 b - - 0 1"); got != -won {
+		t.Errorf("mirrored position %d, want %d", got, -won)
+	}
+}
+
+func TestKQKP(t *testing.T) {
+	initVariables(nil)
+	bishopPawn := evalWhite("7K/7Q/8/8/8/8/1kp5/8 w - - 0 1")
+	centerPawn := evalWhite("7K/7Q/8/8/8/8/3pk3/8 w - - 0 1")
+	if bishopPawn > 200 {
+		t.Errorf("supported bishop pawn on the seventh: %d, want drawish", bishopPawn)
+	}
+	if centerPawn < 500 {
+		t.Errorf("center pawn on the seventh: %d, want a win", centerPawn)
+	}
+}
+
+func TestWrongColoredBishop(t *testing.T) {
+	initVariables(nil)
+	// a8 is a light square: a dark bishop can't drive the king out
+	wrong := evalWhite("k7/8/P7/1K6/8/8/8/4B3 w - - 0 1")
+	right := evalWhite("k7/8/P7/1K6/8/8/8/5B2 w - - 0 1")
+	if wrong < -30 || wrong > 30 {
+		t.Errorf("wrong bishop: %d, want a draw", wrong)
+	}
+	if right < 300 {
+		t.Errorf("right bishop: %d, want a win", right)
+	}
+}
+
+func TestOppositeColoredBishopsScale(t *testing.T) {
+	initVariables(nil)
+	ocb := gm.ParseFen("8/4k3/2b5/3p1p2/3P1P2/2P1B3/4K3/8 w - - 0 1")
+	same := gm.ParseFen("8/4k3/3b4/3p1p2/3P1P2/2P1B3/4K3/8 w - - 0 1")
+	ocbScale := endgameScale(&ocb, GetMaterialEntry(&ocb), GetPawnEntry(&ocb), 100)
+	sameScale := endgameScale(&same, GetMaterialEntry(&same), GetPawnEntry(&same), 100)
+	if ocbScale >= ScaleNormal/2 {
+		t.Errorf("pure opposite-colored bishops scale %d, want well below %d", ocbScale, ScaleNormal)
+	}
+	if sameScale != ScaleNormal {
+		t.Errorf("same-colored bishops scale %d, want %d", sameScale, ScaleNormal)
+	}
+}
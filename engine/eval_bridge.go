@@ -555,10 +555,13 @@ func EndgameKingTerms(b *gm.Board) (centralizationEG int, mopUpEG int) {
 	wPieceCount := bits.OnesCount64(b.White.Bishops | b.White.Knights | b.White.Rooks | b.White.Queens)
 	bPieceCount := bits.OnesCount64(b.Black.Bishops | b.Black.Knights | b.Black.Rooks | b.Black.Queens)
 
-	if wPieceCount > 0 && bPieceCount == 0 {
+	noPawnsLeft := b.White.Pawns == 0 && b.Black.Pawns == 0
+
+	// getKingMopUpBonus already returns black's bonus negated.
+	if wPieceCount > 0 && bPieceCount == 0 && noPawnsLeft {
 		mopUpEG = getKingMopUpBonus(b, true, b.White.Queens > 0, b.White.Rooks > 0)
-	} else if wPieceCount == 0 && bPieceCount > 0 {
-		mopUpEG = -getKingMopUpBonus(b, false, b.Black.Queens > 0, b.Black.Rooks > 0)
+	} else if wPieceCount == 0 && bPieceCount > 0 && noPawnsLeft {
+		mopUpEG = getKingMopUpBonus(b, false, b.Black.Queens > 0, b.Black.Rooks > 0)
 	} else {
 		centralizationEG = kingEndGameCentralizationPenalty(b)
 	}
@@ -619,6 +622,59 @@ func SpaceAndWeakKingDiffs(b *gm.Board) (spaceDiff int, weakKingDiff int) {
 	return
 }
 
+// movementBitboards builds the per-side attack sets of each piece type the way
+// the piece evaluation does.
+func movementBitboards(b *gm.Board) (knightMovementBB, bishopMovementBB, rookMovementBB, queenMovementBB [2]uint64) {
+	all := b.White.All | b.Black.All
+	for side, bb := range [2]*gm.Bitboards{&b.White, &b.Black} {
+		for x := bb.Knights; x != 0; x &= x - 1 {
+			knightMovementBB[side] |= KnightMasks[bits.TrailingZeros64(x)]
+		}
+		for x := bb.Bishops; x != 0; x &= x - 1 {
+			sq := bits.TrailingZeros64(x)
+			bishopMovementBB[side] |= gm.CalculateBishopMoveBitboard(uint8(sq), all&^PositionBB[sq])
+		}
+		for x := bb.Rooks; x != 0; x &= x - 1 {
+			sq := bits.TrailingZeros64(x)
+			rookMovementBB[side] |= gm.CalculateRookMoveBitboard(uint8(sq), all&^PositionBB[sq])
+		}
+		for x := bb.Queens; x != 0; x &= x - 1 {
+			sq := bits.TrailingZeros64(x)
+			queenMovementBB[side] |= gm.CalculateBishopMoveBitboard(uint8(sq), all&^PositionBB[sq]) |
+				gm.CalculateRookMoveBitboard(uint8(sq), all&^PositionBB[sq])
+		}
+	}
+	return
+}
+
+// ThreatDiffs mirrors the engine's threatCounts: per threat kind, the number
+// of white threats minus the number of black threats.
+func ThreatDiffs(b *gm.Board) (diffs [ThreatCount]int) {
+	wPawnAttackBB, bPawnAttackBB, _, _, _, _ := pawnAttackBitboards(b)
+	knightMovementBB, bishopMovementBB, rookMovementBB, queenMovementBB := movementBitboards(b)
+	attacks := attackedSquares(b, wPawnAttackBB, bPawnAttackBB, knightMovementBB, bishopMovementBB, rookMovementBB, queenMovementBB)
+
+	counts := threatCounts(b, wPawnAttackBB, bPawnAttackBB, knightMovementBB, bishopMovementBB, rookMovementBB, queenMovementBB, attacks)
+	for i := range diffs {
+		diffs[i] = counts[0][i] - counts[1][i]
+	}
+	return diffs
+}
+
+// PassedPawnDiffs mirrors the engine's passedPawnCounts: per passed pawn term,
+// the white count minus the black count.
+func PassedPawnDiffs(b *gm.Board) (diffs [PassedTermCount]int) {
+	wPawnAttackBB, bPawnAttackBB, _, _, _, _ := pawnAttackBitboards(b)
+	knightMovementBB, bishopMovementBB, rookMovementBB, queenMovementBB := movementBitboards(b)
+	attacks := attackedSquares(b, wPawnAttackBB, bPawnAttackBB, knightMovementBB, bishopMovementBB, rookMovementBB, queenMovementBB)
+
+	counts := passedPawnCounts(b, GetPawnEntry(b), attacks, GetPiecePhase(b) == 0)
+	for i := range diffs {
+		diffs[i] = counts[0][i] - counts[1][i]
+	}
+	return diffs
+}
+
 // KnightTropismDiffs exposes knight king-tropism MG/EG diffs.
 func KnightTropismDiffs(b *gm.Board) (mg int, eg int) {
 	return knightKingTropism(b)
@@ -668,7 +724,7 @@ func BishopPairDiffsScaled(b *gm.Board) (mg int, eg int) {
 
 // BadBishopDiffs returns MG/EG diffs for the bad-bishop term using blocked pawns.
 func BadBishopDiffs(b *gm.Board) (mg int, eg int) {
-	entry := GetPawnEntry(b, false)
+	entry := GetPawnEntry(b)
 	wLightFixed := bits.OnesCount64(entry.WBlockedBB & lightSquares)
 	wDarkFixed := bits.OnesCount64(entry.WBlockedBB & darkSquares)
 	bLightFixed := bits.OnesCount64(entry.BBlockedBB & lightSquares)
@@ -691,7 +747,7 @@ func BadBishopDiffs(b *gm.Board) (mg int, eg int) {
 
 // BadBishopUnitDiff returns the fixed-pawn count on bishop colors (white minus black).
 func BadBishopUnitDiff(b *gm.Board) int {
-	entry := GetPawnEntry(b, false)
+	entry := GetPawnEntry(b)
 	wLightFixed := bits.OnesCount64(entry.WBlockedBB & lightSquares)
 	wDarkFixed := bits.OnesCount64(entry.WBlockedBB & darkSquares)
 	bLightFixed := bits.OnesCount64(entry.BBlockedBB & lightSquares)
@@ -719,7 +775,7 @@ func BadBishopUnitDiff(b *gm.Board) int {
 
 // KingPasserProximityTerm returns the EG-only king proximity term for passed pawns.
 func KingPasserProximityTerm(b *gm.Board) int {
-	entry := GetPawnEntry(b, false)
+	entry := GetPawnEntry(b)
 	return kingPasserProximity(b, entry)
 }
 
@@ -756,7 +812,7 @@ func PawnStormProxLeverDiffs(b *gm.Board) (stormDiff int, proxDiff int, leverSto
 // All counts are rank-indexed arrays [8]int where index corresponds to attacker's rank.
 func PawnStormCategoryDiffs(b *gm.Board) (freeDiff, leverDiff, weakLeverDiff, blockedDiff [8]int, oppositeSide bool) {
 	// Get pawn hash entry for lever bitboards
-	pawnEntry := GetPawnEntry(b, false)
+	pawnEntry := GetPawnEntry(b)
 
 	// Get king positions and zones
 	wKingSq := bits.TrailingZeros64(b.White.Kings)
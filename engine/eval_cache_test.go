@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+func TestEvalCacheMatchesEvaluation(t *testing.T) {
+	initVariables(nil)
+	defer EvalCacheClear()
+	EvalCacheClear()
+	for _, fen := range evalTraceFens {
+		b := gm.ParseFen(fen)
+		for ply := 0; ply < 30; ply++ {
+			want := Evaluation(&b)
+			// The first call fills the entry, the second reads it back
+			for i := 0; i < 2; i++ {
+				if got := SearchState.staticEval(&b); got != want {
+					t.Fatalf("%s: cached eval %d, Evaluation %d", b.ToFen(), got, want)
+				}
+			}
+			// An unbounded window never takes the lazy shortcut
+			if got := SearchState.lazyStaticEval(&b, -Checkmate, Checkmate); got != want {
+				t.Fatalf("%s: lazy eval %d with an open window, Evaluation %d", b.ToFen(), got, want)
+			}
+			moves := b.GenerateLegalMoves()
+			if len(moves) == 0 {
+				break
+			}
+			b.MakeMove(moves[ply%len(moves)])
+		}
+	}
+}
+
+func TestEvalCacheDoesNotChangeSearch(t *testing.T) {
+	defer func() { EvalCacheEnabled = true }()
+	search := func(fen string, enabled bool) (string, int) {
+		EvalCacheEnabled = enabled
+		b := gm.ParseFen(fen)
+		SearchState.ResetForNewGame()
+		SearchState.SyncPositionState(&b)
+		move := StartSearch(&b, 6, 1000000, 0, 0, true, false, false, false)
+		return move, GetNodeCount()
+	}
+	for _, fen := range evalTraceFens {
+		withMove, withNodes := search(fen, true)
+		withoutMove, withoutNodes := search(fen, false)
+		if withMove != withoutMove || withNodes != withoutNodes {
+			t.Errorf("%s: cache on %s in %d nodes, cache off %s in %d nodes",
+				fen, withMove, withNodes, withoutMove, withoutNodes)
+		}
+	}
+}
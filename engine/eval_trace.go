@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	gm "chess-engine/goosemg"
+)
+
+// =============================================================================
+// EVALUATION TRACE
+// =============================================================================
+// EvaluateWithTrace runs the normal evaluation and records what every term
+// contributed. All values are centipawns from white's point of view. Terms
+// that are computed for each side separately also carry the per-side split,
+// each side's value being its own contribution (so Total = White - Black, up
+// to rounding where the term is scaled after summing). Terms that only exist
+// as a white-minus-black difference have no White and Black.
+
+// EvalScore is a middlegame/endgame pair.
+type EvalScore struct {
+	MG int `json:"mg"`
+	EG int `json:"eg"`
+}
+
+// EvalTerm is one row of the trace.
+type EvalTerm struct {
+	Name  string     `json:"name"`
+	White *EvalScore `json:"white,omitempty"`
+	Black *EvalScore `json:"black,omitempty"`
+	Total EvalScore  `json:"total"`
+}
+
+// EvalTrace is the breakdown of one static evaluation.
+type EvalTrace struct {
+	FEN     string     `json:"fen"`
+	Terms   []EvalTerm `json:"terms"`
+	MG      int        `json:"mg"`                // sum of all terms
+	EG      int        `json:"eg"`                // sum of all terms
+	Endgame string     `json:"endgame,omitempty"` // dedicated endgame evaluation that replaced the terms
+	Phase   int        `json:"phase"`             // weight of MG, out of TotalPhase
+	Scale   int        `json:"scale"`             // EG scale factor, out of ScaleNormal
+	Tapered int32      `json:"tapered"`           // phase-weighted MG/EG
+	Draw    bool       `json:"draw"`              // theoretical draw: tapered score divided by DrawDivider
+	Score   int32      `json:"score"`             // final score, white's point of view
+	Eval    int32      `json:"eval"`              // final score, side to move's point of view (what Evaluation returns)
+}
+
+// EvaluateWithTrace evaluates b and returns the full breakdown.
+func EvaluateWithTrace(b *gm.Board) EvalTrace {
+	tr := EvalTrace{FEN: b.ToFen()}
+	tr.Eval = evaluate(b, &tr)
+	return tr
+}
+
+// add records a term that is only known as a white-minus-black difference.
+func (t *EvalTrace) add(name string, mg, eg int) {
+	t.Terms = append(t.Terms, EvalTerm{Name: name, Total: EvalScore{mg, eg}})
+}
+
+// addSides records a term together with each side's own contribution.
+func (t *EvalTrace) addSides(name string, white, black EvalScore, mg, eg int) {
+	t.Terms = append(t.Terms, EvalTerm{Name: name, White: &white, Black: &black, Total: EvalScore{mg, eg}})
+}
+
+// addSplit records a term whose helper takes one input per side, like
+// isolatedPawnPenalty(wIsolated, bIsolated). Clearing one side's input leaves
+// the other side's share.
+func (t *EvalTrace) addSplit(name string, f func(w, b uint64) (int, int), w, b uint64) {
+	wMG, wEG := f(w, 0)
+	bMG, bEG := f(0, b)
+	t.addSides(name, EvalScore{wMG, wEG}, EvalScore{-bMG, -bEG}, wMG+bMG, wEG+bEG)
+}
+
+// addPsqt records the piece-square table term of one piece type.
+func (t *EvalTrace) addPsqt(name string, pt gm.PieceType, w, b uint64) {
+	t.addSplit(name, func(w, b uint64) (int, int) {
+		return countPieceTables(&w, &b, &PSQT_MG[pt], &PSQT_EG[pt])
+	}, w, b)
+}
+
+// WriteJSON writes the trace as a single JSON object.
+func (t *EvalTrace) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(t)
+}
+
+// WriteTable writes the trace as a human-readable table.
+func (t *EvalTrace) WriteTable(w io.Writer) error {
+	var sb strings.Builder
+	line := " --------------------+-------------+-------------+-------------\n"
+
+	sb.WriteString("                Term |    White    |    Black    |    Total\n")
+	sb.WriteString("                     |   MG    EG  |   MG    EG  |   MG    EG\n")
+	sb.WriteString(line)
+	for _, term := range t.Terms {
+		if term.White != nil {
+			fmt.Fprintf(&sb, " %19s | %5d %5d | %5d %5d | %5d %5d\n", term.Name,
+				term.White.MG, term.White.EG, term.Black.MG, term.Black.EG, term.Total.MG, term.Total.EG)
+		} else {
+			fmt.Fprintf(&sb, " %19s |  ----  ---- |  ----  ---- | %5d %5d\n", term.Name, term.Total.MG, term.Total.EG)
+		}
+	}
+	sb.WriteString(line)
+	fmt.Fprintf(&sb, " %19s |             |             | %5d %5d\n\n", "Total", t.MG, t.EG)
+
+	if t.Endgame != "" {
+		fmt.Fprintf(&sb, "Endgame: %s\n", t.Endgame)
+	}
+	fmt.Fprintf(&sb, "Phase: %d/%d\n", t.Phase, TotalPhase)
+	fmt.Fprintf(&sb, "EG scale: %d/%d\n", t.Scale, ScaleNormal)
+	fmt.Fprintf(&sb, "Tapered evaluation: %+d (white side)\n", t.Tapered)
+	if t.Draw {
+		fmt.Fprintf(&sb, "Theoretical draw: score divided by %d\n", DrawDivider)
+	}
+	fmt.Fprintf(&sb, "Final evaluation: %+d (white side), %+d (side to move)\n", t.Score, t.Eval)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+var evalTraceFens = []string{
+	gm.Startpos,
+	"r1bqkb1r/pppp1ppp/2n2n2/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4",
+	"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R b KQkq - 0 1",
+	"2kr3r/pp3ppp/2n5/3p4/1b1P2q1/2N1B3/PPQ2PPP/2KR3R w - - 0 1",
+	"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+	"8/5k2/8/8/3N4/8/2B5/4K3 b - - 0 1", // mop-up
+	"8/8/4k3/8/8/3NK3/8/8 w - - 0 1",    // theoretical draw
+	"4k3/8/4K3/4P3/8/8/8/8 b - - 0 1",   // KPK
+}
+
+func TestEvaluateWithTraceMatchesEvaluation(t *testing.T) {
+	for _, fen := range evalTraceFens {
+		b := gm.ParseFen(fen)
+		tr := EvaluateWithTrace(&b)
+		if want := Evaluation(&b); tr.Eval != want {
+			t.Errorf("%s: traced eval %d, Evaluation %d", fen, tr.Eval, want)
+		}
+
+		var mg, eg int
+		for _, term := range tr.Terms {
+			mg += term.Total.MG
+			eg += term.Total.EG
+			if term.White == nil {
+				continue
+			}
+			// Mobility of knights and bishops is scaled after summing both sides
+			if strings.HasSuffix(term.Name, "mobility") && term.Name != "Rook mobility" && term.Name != "Queen mobility" {
+				continue
+			}
+			if term.White.MG-term.Black.MG != term.Total.MG || term.White.EG-term.Black.EG != term.Total.EG {
+				t.Errorf("%s: %s sides %+v/%+v don't add up to %+v", fen, term.Name, *term.White, *term.Black, term.Total)
+			}
+		}
+		if mg != tr.MG || eg != tr.EG {
+			t.Errorf("%s: terms sum to %d/%d, trace total %d/%d", fen, mg, eg, tr.MG, tr.EG)
+		}
+	}
+}
+
+func TestEvalTraceOutput(t *testing.T) {
+	b := gm.ParseFen("8/8/4k3/8/8/3NK3/8/8 w - - 0 1")
+	tr := EvaluateWithTrace(&b)
+	if !tr.Draw {
+		t.Fatalf("KNK not flagged as a theoretical draw")
+	}
+
+	var table bytes.Buffer
+	if err := tr.WriteTable(&table); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Material", "Knight mobility", "Theoretical draw", "Final evaluation"} {
+		if !strings.Contains(table.String(), want) {
+			t.Errorf("table is missing %q:\n%s", want, table.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tr.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded EvalTrace
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if decoded.FEN != tr.FEN || decoded.Eval != tr.Eval || len(decoded.Terms) != len(tr.Terms) {
+		t.Errorf("JSON round trip changed the trace")
+	}
+}
@@ -2,7 +2,6 @@ package engine
 
 import (
 	"cmp"
-	"fmt"
 	"math/bits"
 
 	gm "chess-engine/goosemg"
@@ -288,6 +287,38 @@ var KingSafetyTable = [100]int{
 	500, 500, 500, 500, 500, 500, 500, 500, 500, 500,
 }
 
+// Threat kinds, indexes of ThreatMG/ThreatEG
+const (
+	ThreatByPawn     = iota // enemy piece attacked by a pawn
+	ThreatByMinor           // enemy rook or queen attacked by a knight or bishop
+	ThreatByRook            // enemy queen attacked by a rook
+	ThreatHanging           // enemy piece attacked and not defended
+	ThreatByPawnPush        // enemy piece attacked after a safe pawn push
+	ThreatRestricted        // square attacked by both sides, not defended by an enemy pawn
+	ThreatCount
+)
+
+var ThreatMG = [ThreatCount]int{40, 20, 20, 25, 12, 2}
+var ThreatEG = [ThreatCount]int{25, 25, 25, 15, 10, 2}
+
+// Dynamic passed pawn terms, indexes of PassedTermMG/PassedTermEG. All but
+// PassedRookBehind and PassedUnstoppable are weighted by passedRankWeight.
+const (
+	PassedFreePath     = iota // no piece between the passer and its promotion square
+	PassedStopDefended        // stop square attacked by our side
+	PassedStopAttacked        // stop square attacked by the enemy
+	PassedRookBehind          // own rook behind the passer on its file
+	PassedConnected           // another own passer on an adjacent file, at most a rank apart
+	PassedUnstoppable         // pawn ending, enemy king outside the square of the pawn
+	PassedTermCount
+)
+
+var PassedTermMG = [PassedTermCount]int{1, 1, -2, 5, 2, 0}
+var PassedTermEG = [PassedTermCount]int{5, 2, -4, 15, 5, 250}
+
+// passedRankWeight scales the passer terms by relative rank
+var passedRankWeight = [8]int{0, 0, 1, 2, 4, 6, 9, 0}
+
 var ImbalanceRefPawnCount = 5
 var ImbalanceKnightPerPawnMG = 4
 var ImbalanceKnightPerPawnEG = 2
@@ -535,6 +566,77 @@ func weakKingSquaresPenalty(
 	return penaltyMG
 }
 
+/* ============= THREATS ============= */
+
+// attackedSquares returns every square each side attacks, its king included.
+func attackedSquares(
+	b *gm.Board,
+	wPawnAttackBB, bPawnAttackBB uint64,
+	knightMovementBB, bishopMovementBB, rookMovementBB, queenMovementBB [2]uint64,
+) (attacks [2]uint64) {
+	pawnAttacks := [2]uint64{wPawnAttackBB, bPawnAttackBB}
+	kings := [2]uint64{b.White.Kings, b.Black.Kings}
+	for us := 0; us < 2; us++ {
+		attacks[us] = pawnAttacks[us] | knightMovementBB[us] | bishopMovementBB[us] | rookMovementBB[us] | queenMovementBB[us]
+		if kings[us] != 0 {
+			attacks[us] |= KingMoves[bits.TrailingZeros64(kings[us])]
+		}
+	}
+	return attacks
+}
+
+// threatCounts counts, for each side, the threats of every kind it makes
+// against the other side. The movement bitboards are the raw attack sets of
+// each piece type, as built by the piece evaluation, and attacks their union
+// from attackedSquares.
+func threatCounts(
+	b *gm.Board,
+	wPawnAttackBB, bPawnAttackBB uint64,
+	knightMovementBB, bishopMovementBB, rookMovementBB, queenMovementBB [2]uint64,
+	attacks [2]uint64,
+) (counts [2][ThreatCount]int) {
+	pawnAttacks := [2]uint64{wPawnAttackBB, bPawnAttackBB}
+	sides := [2]*gm.Bitboards{&b.White, &b.Black}
+	empty := ^(b.White.All | b.Black.All)
+
+	for us := 0; us < 2; us++ {
+		them := us ^ 1
+		own, enemy := sides[us], sides[them]
+		enemyPieces := enemy.Knights | enemy.Bishops | enemy.Rooks | enemy.Queens
+
+		counts[us][ThreatByPawn] = bits.OnesCount64(pawnAttacks[us] & enemyPieces)
+		counts[us][ThreatByMinor] = bits.OnesCount64((knightMovementBB[us] | bishopMovementBB[us]) & (enemy.Rooks | enemy.Queens))
+		counts[us][ThreatByRook] = bits.OnesCount64(rookMovementBB[us] & enemy.Queens)
+		counts[us][ThreatHanging] = bits.OnesCount64(enemyPieces & attacks[us] &^ attacks[them])
+
+		// Single and double pushes to squares the enemy doesn't hold
+		var pushes uint64
+		if us == 0 {
+			pushes = (own.Pawns << 8) & empty
+			pushes |= ((pushes & onlyRank[2]) << 8) & empty
+		} else {
+			pushes = (own.Pawns >> 8) & empty
+			pushes |= ((pushes & onlyRank[5]) >> 8) & empty
+		}
+		pushes &^= pawnAttacks[them]
+		pushes &= attacks[us] | ^attacks[them]
+		pushE, pushW := PawnCaptureBitboards(pushes, us == 0)
+		counts[us][ThreatByPawnPush] = bits.OnesCount64((pushE | pushW) & enemyPieces &^ pawnAttacks[us])
+
+		counts[us][ThreatRestricted] = bits.OnesCount64(attacks[them] & attacks[us] &^ pawnAttacks[them])
+	}
+	return counts
+}
+
+// threatScore returns the score of one side's threat counts.
+func threatScore(counts *[ThreatCount]int) (threatMG, threatEG int) {
+	for i, n := range counts {
+		threatMG += n * ThreatMG[i]
+		threatEG += n * ThreatEG[i]
+	}
+	return threatMG, threatEG
+}
+
 /* ============= PAWN FUNCTIONS ============= */
 
 func isolatedPawnPenalty(wIsolated uint64, bIsolated uint64) (isolatedMG int, isolatedEG int) {
@@ -567,7 +669,8 @@ func candidatePassedBonus(
 	wLeverPush, bLeverPush uint64,
 ) (bonusMG, bonusEG int, wCandidates, bCandidates uint64) {
 
-	occ := b.White.All | b.Black.All
+	// Pawns only, like the lever pushes: the bonus is part of the pawn hash entry.
+	occ := b.White.Pawns | b.Black.Pawns
 
 	for x := (wLever | wLeverPush) &^ wPassed; x != 0; x &= x - 1 {
 		sq := bits.TrailingZeros64(x)
@@ -640,6 +743,102 @@ func candidatePassedBonus(
 	return
 }
 
+// connectedPassers returns the rank-weighted number of passers in passed that
+// have another passer on an adjacent file at most one rank away.
+func connectedPassers(passed uint64, white bool) (units int) {
+	for x := passed; x != 0; x &= x - 1 {
+		sq := bits.TrailingZeros64(x)
+		file, rank := sq&7, sq/8
+		neighbours := isolatedPawnTable[file] &^ onlyFile[file] & ranksAbove[rank-1] & ranksBelow[rank+1]
+		if passed&neighbours == 0 {
+			continue
+		}
+		if white {
+			units += passedRankWeight[rank]
+		} else {
+			units += passedRankWeight[7-rank]
+		}
+	}
+	return units
+}
+
+// passedPawnCounts counts, for each side, the dynamic passed pawn features of
+// its passers. attacks holds every square each side attacks; the connected
+// passer counts come from the pawn hash entry.
+func passedPawnCounts(b *gm.Board, entry *PawnHashEntry, attacks [2]uint64, pawnEnding bool) (counts [2][PassedTermCount]int) {
+	sides := [2]*gm.Bitboards{&b.White, &b.Black}
+	passed := [2]uint64{entry.WPassedBB, entry.BPassedBB}
+	kingSq := [2]int{bits.TrailingZeros64(b.White.Kings), bits.TrailingZeros64(b.Black.Kings)}
+	occ := b.White.All | b.Black.All
+
+	counts[0][PassedConnected] = entry.WPassedConnected
+	counts[1][PassedConnected] = entry.BPassedConnected
+
+	for us := 0; us < 2; us++ {
+		them := us ^ 1
+		own := sides[us]
+		unstoppable := false
+		for x := passed[us]; x != 0; x &= x - 1 {
+			sq := bits.TrailingZeros64(x)
+			file, rank := sq&7, sq/8
+
+			var relRank, stopSq, promoSq, nearestBehind int
+			var frontSpan, behind uint64
+			if us == 0 {
+				relRank, stopSq, promoSq = rank, sq+8, 56+file
+				frontSpan = onlyFile[file] & ranksAbove[rank+1]
+				behind = onlyFile[file] & ranksBelow[rank-1] & occ
+				nearestBehind = 63 - bits.LeadingZeros64(behind)
+			} else {
+				relRank, stopSq, promoSq = 7-rank, sq-8, file
+				frontSpan = onlyFile[file] & ranksBelow[rank-1]
+				behind = onlyFile[file] & ranksAbove[rank+1] & occ
+				nearestBehind = bits.TrailingZeros64(behind)
+			}
+			weight := passedRankWeight[relRank]
+
+			if frontSpan&occ == 0 {
+				counts[us][PassedFreePath] += weight
+			}
+			if attacks[us]&PositionBB[stopSq] != 0 {
+				counts[us][PassedStopDefended] += weight
+			}
+			if attacks[them]&PositionBB[stopSq] != 0 {
+				counts[us][PassedStopAttacked] += weight
+			}
+			if behind != 0 && own.Rooks&PositionBB[nearestBehind] != 0 {
+				counts[us][PassedRookBehind]++
+			}
+
+			// Rule of the square: the enemy king can't catch a pawn whose path is clear
+			if pawnEnding && !unstoppable && frontSpan&occ == 0 {
+				pawnDist := 7 - relRank
+				if relRank == 1 {
+					pawnDist-- // double push
+				}
+				kingDist := chebyshevDistance(kingSq[them], promoSq)
+				if b.Wtomove == (them == 0) {
+					kingDist--
+				}
+				unstoppable = kingDist > pawnDist
+			}
+		}
+		if unstoppable {
+			counts[us][PassedUnstoppable] = 1
+		}
+	}
+	return counts
+}
+
+// passedPawnTermScore returns the score of one side's passed pawn counts.
+func passedPawnTermScore(counts *[PassedTermCount]int) (passerMG, passerEG int) {
+	for i, n := range counts {
+		passerMG += n * PassedTermMG[i]
+		passerEG += n * PassedTermEG[i]
+	}
+	return passerMG, passerEG
+}
+
 func blockedPawnBonus(wBlocked uint64, bBlocked uint64) (blockedBonusMG int, blockedBonusEG int) {
 	thirdAndFourthRank := onlyRank[2] | onlyRank[3]
 	fifthAndSixthRank := onlyRank[4] | onlyRank[5]
@@ -668,7 +867,7 @@ func pawnWeakLeverPenalty(wWeak uint64, bWeak uint64) (mg int, eg int) {
 	return diffMG, diffEG
 }
 
-func evaluatePawnStorm(b *gm.Board, entry *PawnHashEntry, debug bool) (stormMG int) {
+func evaluatePawnStorm(b *gm.Board, entry *PawnHashEntry) (stormMG int) {
 	// Get king squares and files
 	wKingSq := bits.TrailingZeros64(b.White.Kings)
 	bKingSq := bits.TrailingZeros64(b.Black.Kings)
@@ -1037,13 +1236,10 @@ func evaluateKnights(
 	knightMovementBB *[2]uint64,
 	kingAttackMobilityBB *[2]uint64,
 	attackUnitCounts *[2]int,
-	debug bool,
+	tr *EvalTrace,
 ) (knightMG, knightEG int) {
 
-	knightPsqtMG, knightPsqtEG := countPieceTables(&b.White.Knights, &b.Black.Knights,
-		&PSQT_MG[gm.PieceTypeKnight], &PSQT_EG[gm.PieceTypeKnight])
-
-	var knightMobilityMG, knightMobilityEG int
+	var mobilityMG, mobilityEG [2]int
 
 	for x := b.White.Knights; x != 0; x &= x - 1 {
 		square := bits.TrailingZeros64(x)
@@ -1053,8 +1249,8 @@ func evaluateKnights(
 		mobilitySquares := attackedSquares &^ bPawnAttackBB &^ b.White.All
 		popCnt := bits.OnesCount64(mobilitySquares)
 		idx := mobilityIndex(popCnt, len(KnightMobilityMG)-1)
-		knightMobilityMG += KnightMobilityMG[idx]
-		knightMobilityEG += KnightMobilityEG[idx]
+		mobilityMG[0] += KnightMobilityMG[idx]
+		mobilityEG[0] += KnightMobilityEG[idx]
 		(*attackUnitCounts)[0] += bits.OnesCount64(attackedSquares&innerKingSafetyZones[1]) * attackerInner[gm.PieceTypeKnight]
 		(*attackUnitCounts)[0] += bits.OnesCount64(attackedSquares&outerKingSafetyZones[1]) * attackerOuter[gm.PieceTypeKnight]
 	}
@@ -1066,28 +1262,35 @@ func evaluateKnights(
 		mobilitySquares := attackedSquares &^ wPawnAttackBB &^ b.Black.All
 		popCnt := bits.OnesCount64(mobilitySquares)
 		idx := mobilityIndex(popCnt, len(KnightMobilityMG)-1)
-		knightMobilityMG -= KnightMobilityMG[idx]
-		knightMobilityEG -= KnightMobilityEG[idx]
+		mobilityMG[1] += KnightMobilityMG[idx]
+		mobilityEG[1] += KnightMobilityEG[idx]
 		(*attackUnitCounts)[1] += bits.OnesCount64(attackedSquares&innerKingSafetyZones[0]) * attackerInner[gm.PieceTypeKnight]
 		(*attackUnitCounts)[1] += bits.OnesCount64(attackedSquares&outerKingSafetyZones[0]) * attackerOuter[gm.PieceTypeKnight]
 	}
 
-	knightOutpostMG := KnightOutpostMG*bits.OnesCount64(b.White.Knights&whiteOutposts) -
-		KnightOutpostMG*bits.OnesCount64(b.Black.Knights&blackOutposts)
-	knightOutpostEG := KnightOutpostEG*bits.OnesCount64(b.White.Knights&whiteOutposts) -
-		KnightOutpostEG*bits.OnesCount64(b.Black.Knights&blackOutposts)
+	wOutposts := bits.OnesCount64(b.White.Knights & whiteOutposts)
+	bOutposts := bits.OnesCount64(b.Black.Knights & blackOutposts)
+	knightOutpostMG := KnightOutpostMG*wOutposts - KnightOutpostMG*bOutposts
+	knightOutpostEG := KnightOutpostEG*wOutposts - KnightOutpostEG*bOutposts
 
 	knightTropismBonusMG, knightTropismBonusEG := knightKingTropism(b)
-	knightMobilityMG = (knightMobilityMG * knightMobilityScale) / 100
-
-	knightMG = knightPsqtMG + knightOutpostMG + knightMobilityMG + knightTropismBonusMG
-	knightEG = knightPsqtEG + knightOutpostEG + knightMobilityEG + knightTropismBonusEG
-
-	if debug {
-		println("Knight MG:\t", "PSQT: ", knightPsqtMG, "\tMobility: ", knightMobilityMG,
-			"\tOutpost: ", knightOutpostMG, "\tTropism: ", knightTropismBonusMG)
-		println("Knight EG:\t", "PSQT: ", knightPsqtEG, "\tMobility: ", knightMobilityEG,
-			"\tOutpost: ", knightOutpostEG, "\tTropism: ", knightTropismBonusEG)
+	knightMobilityMG := ((mobilityMG[0] - mobilityMG[1]) * knightMobilityScale) / 100
+	knightMobilityEG := mobilityEG[0] - mobilityEG[1]
+
+	knightMG = knightOutpostMG + knightMobilityMG + knightTropismBonusMG
+	knightEG = knightOutpostEG + knightMobilityEG + knightTropismBonusEG
+
+	if tr != nil {
+		tr.addPsqt("Knight PSQT", gm.PieceTypeKnight, b.White.Knights, b.Black.Knights)
+		tr.addSides("Knight mobility",
+			EvalScore{mobilityMG[0] * knightMobilityScale / 100, mobilityEG[0]},
+			EvalScore{mobilityMG[1] * knightMobilityScale / 100, mobilityEG[1]},
+			knightMobilityMG, knightMobilityEG)
+		tr.addSides("Knight outposts",
+			EvalScore{KnightOutpostMG * wOutposts, KnightOutpostEG * wOutposts},
+			EvalScore{KnightOutpostMG * bOutposts, KnightOutpostEG * bOutposts},
+			knightOutpostMG, knightOutpostEG)
+		tr.add("Knight tropism", knightTropismBonusMG, knightTropismBonusEG)
 	}
 
 	return knightMG, knightEG
@@ -1104,14 +1307,11 @@ func evaluateBishops(
 	bishopMovementBB *[2]uint64,
 	kingAttackMobilityBB *[2]uint64,
 	attackUnitCounts *[2]int,
-	debug bool,
+	tr *EvalTrace,
 ) (bishopMG, bishopEG int) {
 
-	bishopPsqtMG, bishopPsqtEG := countPieceTables(&b.White.Bishops, &b.Black.Bishops,
-		&PSQT_MG[gm.PieceTypeBishop], &PSQT_EG[gm.PieceTypeBishop])
-
-	var bishopMobilityMG, bishopMobilityEG int
-	var bishopBadMG, bishopBadEG int
+	var mobilityMG, mobilityEG [2]int
+	var badMG, badEG [2]int
 
 	// Prepare pawn color layout
 	wLightFixed := bits.OnesCount64(wBlockedPawns & lightSquares)
@@ -1122,8 +1322,8 @@ func evaluateBishops(
 	for x := b.White.Bishops; x != 0; x &= x - 1 {
 		square := bits.TrailingZeros64(x)
 		wBishopBadMG, wBishopBadEG := badBishopPenalty(square, wDarkFixed, wLightFixed)
-		bishopBadMG += wBishopBadMG
-		bishopBadEG += wBishopBadEG
+		badMG[0] += wBishopBadMG
+		badEG[0] += wBishopBadEG
 		occupied := allPieces &^ PositionBB[square]
 		bishopAttacks := gm.CalculateBishopMoveBitboard(uint8(square), occupied)
 		(*kingAttackMobilityBB)[0] |= bishopAttacks &^ b.White.All
@@ -1131,16 +1331,16 @@ func evaluateBishops(
 		mobilitySquares := bishopAttacks &^ bPawnAttackBB &^ b.White.All
 		popCnt := bits.OnesCount64(mobilitySquares)
 		idx := mobilityIndex(popCnt, len(BishopMobilityMG)-1)
-		bishopMobilityMG += BishopMobilityMG[idx]
-		bishopMobilityEG += BishopMobilityEG[idx]
+		mobilityMG[0] += BishopMobilityMG[idx]
+		mobilityEG[0] += BishopMobilityEG[idx]
 		(*attackUnitCounts)[0] += bits.OnesCount64(bishopAttacks&innerKingSafetyZones[1]) * attackerInner[gm.PieceTypeBishop]
 		(*attackUnitCounts)[0] += bits.OnesCount64(bishopAttacks&outerKingSafetyZones[1]) * attackerOuter[gm.PieceTypeBishop]
 	}
 	for x := b.Black.Bishops; x != 0; x &= x - 1 {
 		square := bits.TrailingZeros64(x)
 		bBishopBadMG, bBishopBadEG := badBishopPenalty(square, bDarkFixed, bLightFixed)
-		bishopBadMG -= bBishopBadMG
-		bishopBadEG -= bBishopBadEG
+		badMG[1] += bBishopBadMG
+		badEG[1] += bBishopBadEG
 		occupied := allPieces &^ PositionBB[square]
 		bishopAttacks := gm.CalculateBishopMoveBitboard(uint8(square), occupied)
 		(*kingAttackMobilityBB)[1] |= bishopAttacks &^ b.Black.All
@@ -1148,30 +1348,39 @@ func evaluateBishops(
 		mobilitySquares := bishopAttacks &^ wPawnAttackBB &^ b.Black.All
 		popCnt := bits.OnesCount64(mobilitySquares)
 		idx := mobilityIndex(popCnt, len(BishopMobilityMG)-1)
-		bishopMobilityMG -= BishopMobilityMG[idx]
-		bishopMobilityEG -= BishopMobilityEG[idx]
+		mobilityMG[1] += BishopMobilityMG[idx]
+		mobilityEG[1] += BishopMobilityEG[idx]
 		(*attackUnitCounts)[1] += bits.OnesCount64(bishopAttacks&innerKingSafetyZones[0]) * attackerInner[gm.PieceTypeBishop]
 		(*attackUnitCounts)[1] += bits.OnesCount64(bishopAttacks&outerKingSafetyZones[0]) * attackerOuter[gm.PieceTypeBishop]
 	}
 
-	bishopOutpostMG := BishopOutpostMG*bits.OnesCount64(b.White.Bishops&whiteOutposts) -
-		BishopOutpostMG*bits.OnesCount64(b.Black.Bishops&blackOutposts)
-	bishopOutpostEG := BishopOutpostEG*bits.OnesCount64(b.White.Bishops&whiteOutposts) -
-		BishopOutpostEG*bits.OnesCount64(b.Black.Bishops&blackOutposts)
+	wOutposts := bits.OnesCount64(b.White.Bishops & whiteOutposts)
+	bOutposts := bits.OnesCount64(b.Black.Bishops & blackOutposts)
+	bishopOutpostMG := BishopOutpostMG*wOutposts - BishopOutpostMG*bOutposts
+	bishopOutpostEG := BishopOutpostEG*wOutposts - BishopOutpostEG*bOutposts
 
 	bishopPairMG, bishopPairEG := bishopPairBonuses(b)
 	bishopPairMG = (bishopPairMG * bishopPairScaleMG) / 100
 
-	bishopMobilityMG = (bishopMobilityMG * bishopMobilityScale) / 100
-
-	bishopMG = bishopPsqtMG + bishopOutpostMG + bishopPairMG + bishopMobilityMG + bishopBadMG
-	bishopEG = bishopPsqtEG + bishopOutpostEG + bishopPairEG + bishopMobilityEG + bishopBadEG
-
-	if debug {
-		println("Bishop MG:\t", "PSQT: ", bishopPsqtMG, "\tMobility: ", bishopMobilityMG,
-			"\tOutpost: ", bishopOutpostMG, "\tPair: ", bishopPairMG, "\tBadBishop: ", bishopBadMG)
-		println("Bishop EG:\t", "PSQT: ", bishopPsqtEG, "\tMobility: ", bishopMobilityEG,
-			"\tOutpost: ", bishopOutpostEG, "\tPair: ", bishopPairEG, "\tBadBishop: ", bishopBadEG)
+	bishopMobilityMG := ((mobilityMG[0] - mobilityMG[1]) * bishopMobilityScale) / 100
+	bishopMobilityEG := mobilityEG[0] - mobilityEG[1]
+	bishopBadMG, bishopBadEG := badMG[0]-badMG[1], badEG[0]-badEG[1]
+
+	bishopMG = bishopOutpostMG + bishopPairMG + bishopMobilityMG + bishopBadMG
+	bishopEG = bishopOutpostEG + bishopPairEG + bishopMobilityEG + bishopBadEG
+
+	if tr != nil {
+		tr.addPsqt("Bishop PSQT", gm.PieceTypeBishop, b.White.Bishops, b.Black.Bishops)
+		tr.addSides("Bishop mobility",
+			EvalScore{mobilityMG[0] * bishopMobilityScale / 100, mobilityEG[0]},
+			EvalScore{mobilityMG[1] * bishopMobilityScale / 100, mobilityEG[1]},
+			bishopMobilityMG, bishopMobilityEG)
+		tr.addSides("Bishop outposts",
+			EvalScore{BishopOutpostMG * wOutposts, BishopOutpostEG * wOutposts},
+			EvalScore{BishopOutpostMG * bOutposts, BishopOutpostEG * bOutposts},
+			bishopOutpostMG, bishopOutpostEG)
+		tr.add("Bishop pair", bishopPairMG, bishopPairEG)
+		tr.addSides("Bad bishop", EvalScore{badMG[0], badEG[0]}, EvalScore{badMG[1], badEG[1]}, bishopBadMG, bishopBadEG)
 	}
 
 	return bishopMG, bishopEG
@@ -1187,13 +1396,10 @@ func evaluateRooks(
 	rookMovementBB *[2]uint64,
 	kingAttackMobilityBB *[2]uint64,
 	attackUnitCounts *[2]int,
-	debug bool,
+	tr *EvalTrace,
 ) (rookMG, rookEG int) {
 
-	rookPsqtMG, rookPsqtEG := countPieceTables(&b.White.Rooks, &b.Black.Rooks,
-		&PSQT_MG[gm.PieceTypeRook], &PSQT_EG[gm.PieceTypeRook])
-
-	var rookMobilityMG, rookMobilityEG int
+	var mobilityMG, mobilityEG [2]int
 
 	for x := b.White.Rooks; x != 0; x &= x - 1 {
 		square := bits.TrailingZeros64(x)
@@ -1204,8 +1410,8 @@ func evaluateRooks(
 		mobilitySquares := rookAttacks &^ bPawnAttackBB &^ b.White.All
 		popCnt := bits.OnesCount64(mobilitySquares)
 		idx := mobilityIndex(popCnt, len(RookMobilityMG)-1)
-		rookMobilityMG += RookMobilityMG[idx]
-		rookMobilityEG += RookMobilityEG[idx]
+		mobilityMG[0] += RookMobilityMG[idx]
+		mobilityEG[0] += RookMobilityEG[idx]
 		(*attackUnitCounts)[0] += bits.OnesCount64(rookAttacks&innerKingSafetyZones[1]) * attackerInner[gm.PieceTypeRook]
 		(*attackUnitCounts)[0] += bits.OnesCount64(rookAttacks&outerKingSafetyZones[1]) * attackerOuter[gm.PieceTypeRook]
 	}
@@ -1218,25 +1424,31 @@ func evaluateRooks(
 		mobilitySquares := rookAttacks &^ wPawnAttackBB &^ b.Black.All
 		popCnt := bits.OnesCount64(mobilitySquares)
 		idx := mobilityIndex(popCnt, len(RookMobilityMG)-1)
-		rookMobilityMG -= RookMobilityMG[idx]
-		rookMobilityEG -= RookMobilityEG[idx]
+		mobilityMG[1] += RookMobilityMG[idx]
+		mobilityEG[1] += RookMobilityEG[idx]
 		(*attackUnitCounts)[1] += bits.OnesCount64(rookAttacks&innerKingSafetyZones[0]) * attackerInner[gm.PieceTypeRook]
 		(*attackUnitCounts)[1] += bits.OnesCount64(rookAttacks&outerKingSafetyZones[0]) * attackerOuter[gm.PieceTypeRook]
 	}
 
+	rookMobilityMG := mobilityMG[0] - mobilityMG[1]
+	rookMobilityEG := mobilityEG[0] - mobilityEG[1]
+
 	rookSemiOpenMG, rookOpenMG := rookFilesBonus(b, openFiles, wSemiOpenFiles, bSemiOpenFiles)
 	rookStackedMG := rookStackBonusMG(wRookStackFiles, bRookStackFiles)
 
 	rookSeventhBonusEG := rookSeventhRankBonus(b)
 
-	rookMG = rookPsqtMG + rookMobilityMG + rookOpenMG + rookSemiOpenMG + rookStackedMG
-	rookEG = rookPsqtEG + rookMobilityEG + rookSeventhBonusEG
-
-	if debug {
-		println("Rook MG:\t", "PSQT: ", rookPsqtMG, "\tMobility: ", rookMobilityMG,
-			"\tOpen: ", rookOpenMG, "\tSemi-open: ", rookSemiOpenMG, "\tStacked: ", rookStackedMG)
-		println("Rook EG:\t", "PSQT: ", rookPsqtEG, "\tMobility: ", rookMobilityEG,
-			"\tSeventh: ", rookSeventhBonusEG)
+	rookMG = rookMobilityMG + rookOpenMG + rookSemiOpenMG + rookStackedMG
+	rookEG = rookMobilityEG + rookSeventhBonusEG
+
+	if tr != nil {
+		tr.addPsqt("Rook PSQT", gm.PieceTypeRook, b.White.Rooks, b.Black.Rooks)
+		tr.addSides("Rook mobility", EvalScore{mobilityMG[0], mobilityEG[0]}, EvalScore{mobilityMG[1], mobilityEG[1]},
+			rookMobilityMG, rookMobilityEG)
+		tr.add("Rook open file", rookOpenMG, 0)
+		tr.add("Rook semi-open", rookSemiOpenMG, 0)
+		tr.add("Rooks stacked", rookStackedMG, 0)
+		tr.add("Rook on seventh", 0, rookSeventhBonusEG)
 	}
 
 	return rookMG, rookEG
@@ -1250,13 +1462,10 @@ func evaluateQueens(
 	queenMovementBB *[2]uint64,
 	kingAttackMobilityBB *[2]uint64,
 	attackUnitCounts *[2]int,
-	debug bool,
+	tr *EvalTrace,
 ) (queenMG, queenEG int) {
 
-	queenPsqtMG, queenPsqtEG := countPieceTables(&b.White.Queens, &b.Black.Queens,
-		&PSQT_MG[gm.PieceTypeQueen], &PSQT_EG[gm.PieceTypeQueen])
-
-	var queenMobilityMG, queenMobilityEG int
+	var mobilityMG, mobilityEG [2]int
 
 	for x := b.White.Queens; x != 0; x &= x - 1 {
 		square := bits.TrailingZeros64(x)
@@ -1269,8 +1478,8 @@ func evaluateQueens(
 		mobilitySquares := attackedSquares &^ bPawnAttackBB &^ b.White.All
 		popCnt := bits.OnesCount64(mobilitySquares)
 		idx := mobilityIndex(popCnt, len(QueenMobilityMG)-1)
-		queenMobilityMG += QueenMobilityMG[idx]
-		queenMobilityEG += QueenMobilityEG[idx]
+		mobilityMG[0] += QueenMobilityMG[idx]
+		mobilityEG[0] += QueenMobilityEG[idx]
 		(*attackUnitCounts)[0] += bits.OnesCount64(attackedSquares&innerKingSafetyZones[1]) * attackerInner[gm.PieceTypeQueen]
 		(*attackUnitCounts)[0] += bits.OnesCount64(attackedSquares&outerKingSafetyZones[1]) * attackerOuter[gm.PieceTypeQueen]
 	}
@@ -1285,32 +1494,109 @@ func evaluateQueens(
 		mobilitySquares := attackedSquares &^ wPawnAttackBB &^ b.Black.All
 		popCnt := bits.OnesCount64(mobilitySquares)
 		idx := mobilityIndex(popCnt, len(QueenMobilityMG)-1)
-		queenMobilityMG -= QueenMobilityMG[idx]
-		queenMobilityEG -= QueenMobilityEG[idx]
+		mobilityMG[1] += QueenMobilityMG[idx]
+		mobilityEG[1] += QueenMobilityEG[idx]
 		(*attackUnitCounts)[1] += bits.OnesCount64(attackedSquares&innerKingSafetyZones[0]) * attackerInner[gm.PieceTypeQueen]
 		(*attackUnitCounts)[1] += bits.OnesCount64(attackedSquares&outerKingSafetyZones[0]) * attackerOuter[gm.PieceTypeQueen]
 	}
 
+	queenMobilityMG := mobilityMG[0] - mobilityMG[1]
+	queenMobilityEG := mobilityEG[0] - mobilityEG[1]
+
 	centralizedQueenBonus := centralizedQueen(b)
 
-	queenMG = queenPsqtMG + queenMobilityMG
-	queenEG = queenPsqtEG + queenMobilityEG + centralizedQueenBonus
+	queenMG = queenMobilityMG
+	queenEG = queenMobilityEG + centralizedQueenBonus
 
-	if debug {
-		println("Queen MG:\t", "PSQT: ", queenPsqtMG, "\tMobility: ", queenMobilityMG)
-		println("Queen EG:\t", "PSQT: ", queenPsqtEG, "\tMobility: ", queenMobilityEG,
-			"\tCentralized: ", centralizedQueenBonus)
+	if tr != nil {
+		tr.addPsqt("Queen PSQT", gm.PieceTypeQueen, b.White.Queens, b.Black.Queens)
+		tr.addSides("Queen mobility", EvalScore{mobilityMG[0], mobilityEG[0]}, EvalScore{mobilityMG[1], mobilityEG[1]},
+			queenMobilityMG, queenMobilityEG)
+		tr.add("Queen centralized", 0, centralizedQueenBonus)
 	}
 
 	return queenMG, queenEG
 }
 
+/* ============= LAZY EVALUATION ============= */
+
+// LazyEvalMargin is how far outside the search window the lazy eval has to be
+// before quiescence trusts it instead of running the full evaluation.
+var LazyEvalMargin int32 = 700
+
+// lazyEvaluation returns the part of the evaluation that needs no attack maps
+// (material, imbalance, piece-square tables, the pawn hash score and tempo),
+// from the side to move's point of view. ok is false for positions the full
+// evaluation treats specially, dedicated endgames and theoretical draws.
+func lazyEvaluation(b *gm.Board) (score int32, ok bool) {
+	mat := GetMaterialEntry(b)
+	if mat.endgame != nil || mat.Draw {
+		return 0, false
+	}
+	pawnEntry := GetPawnEntry(b)
+
+	// Material and the piece PSQT come from the board's running sums; the
+	// pawn PSQT is part of the pawn entry's score.
+	psqtMG, psqtEG := psqtScore(b)
+	mgScore := psqtMG + mat.ImbalanceMG + pawnEntry.PawnScoreMG
+	egScore := psqtEG + mat.ImbalanceEG + pawnEntry.PawnScoreEG
+	if b.Wtomove {
+		mgScore += TempoBonus
+		egScore += TempoBonus
+	} else {
+		mgScore -= TempoBonus
+		egScore -= TempoBonus
+	}
+
+	score = int32((mgScore*mat.Phase + egScore*(TotalPhase-mat.Phase)) / TotalPhase)
+	if !b.Wtomove {
+		score = -score
+	}
+	return score, true
+}
+
 /* ============= MAIN EVALUATION ============= */
-func Evaluation(b *gm.Board, debug bool) (score int32) {
+// Evaluation returns the static evaluation of b from the side to move's point of view.
+func Evaluation(b *gm.Board) int32 {
+	return evaluate(b, nil)
+}
+
+// evaluate is Evaluation, recording every term into tr when it isn't nil.
+func evaluate(b *gm.Board, tr *EvalTrace) (score int32) {
+	// ===========================================
+	// MATERIAL_HASH: Get cached material data
+	// ===========================================
+	mat := GetMaterialEntry(b)
+
+	// ===========================================
+	// ENDGAMES: dedicated evaluation by material
+	// ===========================================
+	if score, name, ok := evaluateEndgame(b); ok {
+		if tr != nil {
+			tr.add("Endgame "+name, int(score), int(score))
+			tr.Endgame = name
+			tr.MG, tr.EG = int(score), int(score)
+			tr.Phase = mat.Phase
+			tr.Scale = ScaleNormal
+			tr.Tapered, tr.Score = score, score
+		}
+		if !b.Wtomove {
+			score = -score
+		}
+		return score
+	}
+
 	// ===========================================
 	// PAWN_HASH: Get cached pawn structure
 	// ===========================================
-	pawnEntry := GetPawnEntry(b, debug)
+	var pawnEntry *PawnHashEntry
+	if tr != nil {
+		// Recompute so the pawn terms get recorded
+		entry := ComputePawnEntry(b, tr)
+		pawnEntry = &entry
+	} else {
+		pawnEntry = GetPawnEntry(b)
+	}
 
 	wPawnAttackBB := pawnEntry.WPawnAttackBB
 	bPawnAttackBB := pawnEntry.BPawnAttackBB
@@ -1322,8 +1608,11 @@ func Evaluation(b *gm.Board, debug bool) (score int32) {
 	pawnMG := pawnEntry.PawnScoreMG
 	pawnEG := pawnEntry.PawnScoreEG
 
-	stormMG := evaluatePawnStorm(b, pawnEntry, debug)
+	stormMG := evaluatePawnStorm(b, pawnEntry)
 	pawnMG += stormMG
+	if tr != nil {
+		tr.add("Pawn storm", stormMG, 0)
+	}
 
 	// Outposts for knights/bishops
 	outposts := getOutpostsBB(b, wPawnAttackBB, bPawnAttackBB)
@@ -1358,9 +1647,6 @@ func Evaluation(b *gm.Board, debug bool) (score int32) {
 	var queenMG, queenEG int
 	var kingMG, kingEG int
 
-	var wMaterialMG, wMaterialEG = countMaterial(&b.White)
-	var bMaterialMG, bMaterialEG = countMaterial(&b.Black)
-
 	// King safety setup
 	var attackUnitCounts = [2]int{0, 0}
 	innerKingSafetyZones := getKingSafetyTable(b, true, wPawnAttackBB, bPawnAttackBB)
@@ -1371,34 +1657,6 @@ func Evaluation(b *gm.Board, debug bool) (score int32) {
 	wPawnCount := bits.OnesCount64(b.White.Pawns)
 	bPawnCount := bits.OnesCount64(b.Black.Pawns)
 
-	// Variables for debug output still needed outside helpers
-	var kingPsqtMG, kingPsqtEG int
-
-	if debug {
-		println("################### FEN ###################")
-		println("FEN: ", b.ToFen())
-		println("################### PAWN HASH ###################")
-		println("Pawn MG (cached): ", pawnEntry.PawnScoreMG)
-		println("Pawn EG (cached): ", pawnEntry.PawnScoreEG)
-		println("Pawn storm MG: ", stormMG)
-
-		println("################### PAWN BITBOARDS ###################")
-		fmt.Printf("BB Pawn attacks W/B: %016x / %016x\n", pawnEntry.WPawnAttackBB, pawnEntry.BPawnAttackBB)
-		fmt.Printf("BB Files open | wSemi | bSemi: %016x | %016x | %016x\n", openFiles, wSemiOpenFiles, bSemiOpenFiles)
-		fmt.Printf("BB Passed W/B: %016x / %016x\n", pawnEntry.WPassedBB, pawnEntry.BPassedBB)
-		fmt.Printf("BB Isolated W/B: %016x / %016x\n", pawnEntry.WIsolatedBB, pawnEntry.BIsolatedBB)
-		fmt.Printf("BB Backward W/B: %016x / %016x\n", pawnEntry.WBackwardBB, pawnEntry.BBackwardBB)
-		fmt.Printf("BB Blocked W/B: %016x / %016x\n", pawnEntry.WBlockedBB, pawnEntry.BBlockedBB)
-		fmt.Printf("BB Lever W/B: %016x / %016x\n", pawnEntry.WLeverBB, pawnEntry.BLeverBB)
-		fmt.Printf("BB Weak lever W/B: %016x / %016x\n", pawnEntry.WWeakLeverBB, pawnEntry.BWeakLeverBB)
-		fmt.Printf("BB Candidate W/B: %016x / %016x\n", pawnEntry.WCandidateBB, pawnEntry.BCandidateBB)
-
-		println("################### EXTRA BITBOARDS ###################")
-		fmt.Printf("BB Outpost W/B: %016x / %016x\n", whiteOutposts, blackOutposts)
-
-		println("################### PIECE PARAMETERS ###################")
-	}
-
 	allPieces := b.White.All | b.Black.All
 
 	// KNIGHTS
@@ -1411,7 +1669,7 @@ func Evaluation(b *gm.Board, debug bool) (score int32) {
 		&knightMovementBB,
 		&kingAttackMobilityBB,
 		&attackUnitCounts,
-		debug,
+		tr,
 	)
 
 	// BISHOPS
@@ -1426,7 +1684,7 @@ func Evaluation(b *gm.Board, debug bool) (score int32) {
 		&bishopMovementBB,
 		&kingAttackMobilityBB,
 		&attackUnitCounts,
-		debug,
+		tr,
 	)
 
 	// ROOKS
@@ -1440,7 +1698,7 @@ func Evaluation(b *gm.Board, debug bool) (score int32) {
 		&rookMovementBB,
 		&kingAttackMobilityBB,
 		&attackUnitCounts,
-		debug,
+		tr,
 	)
 
 	// QUEENS
@@ -1452,12 +1710,10 @@ func Evaluation(b *gm.Board, debug bool) (score int32) {
 		&queenMovementBB,
 		&kingAttackMobilityBB,
 		&attackUnitCounts,
-		debug,
+		tr,
 	)
 
 	// KING (unchanged, but now uses attackUnitCounts and kingAttackMobilityBB filled by helpers)
-	kingPsqtMG, kingPsqtEG = countPieceTables(&b.White.Kings, &b.Black.Kings, &PSQT_MG[gm.PieceTypeKing], &PSQT_EG[gm.PieceTypeKing])
-
 	kingAttackPenaltyMG, kingAttackPenaltyEG := kingAttackCountPenalty(&attackUnitCounts)
 	kingPawnShieldPenaltyMG := kingFilesPenalty(b, openFiles, wSemiOpenFiles, bSemiOpenFiles)
 	KingMinorPieceDefenseBonusMG := kingMinorPieceDefences(innerKingSafetyZones, knightMovementBB, bishopMovementBB)
@@ -1470,7 +1726,7 @@ func Evaluation(b *gm.Board, debug bool) (score int32) {
 	kingCentralManhattanPenalty := 0
 	kingMopUpBonus := 0
 
-	piecePhase := GetPiecePhase(b)
+	piecePhase := mat.Phase
 
 	if (piecePhase < 16 && bits.OnesCount64(b.White.Queens|b.Black.Queens) == 0) || piecePhase < 10 {
 		noPawnsLeft := wPawnCount == 0 && bPawnCount == 0
@@ -1483,75 +1739,96 @@ func Evaluation(b *gm.Board, debug bool) (score int32) {
 		}
 	}
 
-	kingMG = kingPsqtMG + kingAttackPenaltyMG + kingPawnShieldPenaltyMG + KingMinorPieceDefenseBonusMG + kingPawnDefenseMG
-	kingEG = kingPsqtEG + kingAttackPenaltyEG + kingCentralManhattanPenalty + kingMopUpBonus + kingPasserProximityEG
-
-	if debug {
-		println("King MG:\t", "PSQT: ", kingPsqtMG, "\tAttack: ", kingAttackPenaltyMG,
-			"\tFile: ", kingPawnShieldPenaltyMG, "\tMinorDefense: ", KingMinorPieceDefenseBonusMG,
-			"\tPawnDefense: ", kingPawnDefenseMG)
-		println("King EG:\t", "PSQT: ", kingPsqtEG, "\tAttack: ", kingAttackPenaltyEG,
-			"\tCmd: ", kingCentralManhattanPenalty, "\tMopUp: ", kingMopUpBonus,
-			"\tPawnDefense: ", kingPawnDefenseMG, "\tPasser proximity: ", kingPasserProximityEG)
-	}
-
-	if debug {
-		println("################### FINAL PIECE EVALUATION ###################")
-		println("Pawn: ", pawnMG, ":", pawnEG)
-		println("Knight: ", knightMG, ":", knightEG)
-		println("Bishop: ", bishopMG, ":", bishopEG)
-		println("Rook: ", rookMG, ":", rookEG)
-		println("Queen: ", queenMG, ":", queenEG)
-		println("King: ", kingMG, ":", kingEG)
-	}
-
-	if debug {
-		println("################### SPACE EVALUATION ###################")
+	kingMG = kingAttackPenaltyMG + kingPawnShieldPenaltyMG + KingMinorPieceDefenseBonusMG + kingPawnDefenseMG
+	kingEG = kingAttackPenaltyEG + kingCentralManhattanPenalty + kingMopUpBonus + kingPasserProximityEG
+
+	if tr != nil {
+		tr.addPsqt("King PSQT", gm.PieceTypeKing, b.White.Kings, b.Black.Kings)
+		tr.add("King attacks", kingAttackPenaltyMG, kingAttackPenaltyEG)
+		tr.add("King open files", kingPawnShieldPenaltyMG, 0)
+		tr.add("King minor defense", KingMinorPieceDefenseBonusMG, 0)
+		tr.add("King pawn defense", kingPawnDefenseMG, 0)
+		tr.add("King passer prox.", 0, kingPasserProximityEG)
+		tr.add("King centralization", 0, kingCentralManhattanPenalty)
+		tr.add("King mop-up", 0, kingMopUpBonus)
 	}
 
 	// Weak squares & protected squares (unchanged call)
 	spaceMG, spaceEG := spaceEvaluation(b, wPawnAttackBB, bPawnAttackBB, knightMovementBB, bishopMovementBB, piecePhase)
 	weakKingMG := weakKingSquaresPenalty(b, wPawnAttackBB, bPawnAttackBB, innerKingSafetyZones)
 
-	// FINAL SCORE CALCULATION (unchanged)
-	materialScoreMG := wMaterialMG - bMaterialMG
-	materialScoreEG := wMaterialEG - bMaterialEG
+	attacks := attackedSquares(b, wPawnAttackBB, bPawnAttackBB, knightMovementBB, bishopMovementBB, rookMovementBB, queenMovementBB)
+	threats := threatCounts(b, wPawnAttackBB, bPawnAttackBB, knightMovementBB, bishopMovementBB, rookMovementBB, queenMovementBB, attacks)
+	wThreatMG, wThreatEG := threatScore(&threats[0])
+	bThreatMG, bThreatEG := threatScore(&threats[1])
+	threatMG, threatEG := wThreatMG-bThreatMG, wThreatEG-bThreatEG
+
+	passers := passedPawnCounts(b, pawnEntry, attacks, piecePhase == 0)
+	wPasserMG, wPasserEG := passedPawnTermScore(&passers[0])
+	bPasserMG, bPasserEG := passedPawnTermScore(&passers[1])
+	passerMG, passerEG := wPasserMG-bPasserMG, wPasserEG-bPasserEG
+
+	// FINAL SCORE CALCULATION
+	// Material and the piece PSQT (the pawn PSQT is in pawnMG/EG) come from
+	// the board's running sums instead of a per-piece recount.
+	psqtMG, psqtEG := psqtScore(b)
 
 	toMoveBonus := TempoBonus
 	if !b.Wtomove {
 		toMoveBonus = -TempoBonus
 	}
 
-	imbalanceMG, imbalanceEG := materialImbalance(b)
-
-	if debug {
-		println("################### SPACE EVALUATION ###################")
-		println("Space: ", spaceMG, ":", spaceEG)
-		println("Weak king MG: ", weakKingMG)
+	imbalanceMG, imbalanceEG := mat.ImbalanceMG, mat.ImbalanceEG
+
+	if tr != nil {
+		tr.add("Space", spaceMG, spaceEG)
+		tr.add("Weak king squares", weakKingMG, 0)
+		tr.addSides("Threats", EvalScore{wThreatMG, wThreatEG}, EvalScore{bThreatMG, bThreatEG}, threatMG, threatEG)
+		tr.addSides("Passer dynamics", EvalScore{wPasserMG, wPasserEG}, EvalScore{bPasserMG, bPasserEG}, passerMG, passerEG)
+		tr.add("Imbalance", imbalanceMG, imbalanceEG)
+		tempo := EvalScore{TempoBonus, TempoBonus}
+		if b.Wtomove {
+			tr.addSides("Tempo", tempo, EvalScore{}, toMoveBonus, toMoveBonus)
+		} else {
+			tr.addSides("Tempo", EvalScore{}, tempo, toMoveBonus, toMoveBonus)
+		}
+		wMaterialMG, wMaterialEG := countMaterial(&b.White)
+		bMaterialMG, bMaterialEG := countMaterial(&b.Black)
+		tr.addSides("Material", EvalScore{wMaterialMG, wMaterialEG}, EvalScore{bMaterialMG, bMaterialEG},
+			mat.MaterialMG, mat.MaterialEG)
 	}
 
-	variableScoreMG := pawnMG + knightMG + bishopMG + rookMG + queenMG + kingMG + toMoveBonus + imbalanceMG + spaceMG + weakKingMG
-	variableScoreEG := pawnEG + knightEG + bishopEG + rookEG + queenEG + kingEG + toMoveBonus + imbalanceEG + spaceEG
+	variableScoreMG := pawnMG + knightMG + bishopMG + rookMG + queenMG + kingMG + toMoveBonus + imbalanceMG + spaceMG + weakKingMG + threatMG + passerMG
+	variableScoreEG := pawnEG + knightEG + bishopEG + rookEG + queenEG + kingEG + toMoveBonus + imbalanceEG + spaceEG + threatEG + passerEG
 
-	mgScore := materialScoreMG + variableScoreMG
-	egScore := materialScoreEG + variableScoreEG
+	mgScore := psqtMG + variableScoreMG
+	egScore := psqtEG + variableScoreEG
+
+	if tr != nil {
+		tr.MG, tr.EG = mgScore, egScore
+	}
+
+	scale := endgameScale(b, mat, pawnEntry, egScore)
+	egScore = egScore * scale / ScaleNormal
 
 	mgWeight := piecePhase
 	egWeight := TotalPhase - piecePhase
 	score = int32((mgScore*mgWeight + egScore*egWeight) / TotalPhase)
+	if tr != nil {
+		tr.Phase = piecePhase
+		tr.Scale = scale
+		tr.Tapered = score
+	}
 
-	if isTheoreticalDraw(b, debug) {
+	if mat.Draw {
 		score = score / DrawDivider
+		if tr != nil {
+			tr.Draw = true
+		}
 	}
 
-	if debug {
-		println("################### MATERIAL ###################")
-		println("Material: ", materialScoreMG, ":", materialScoreEG)
-		println("Variable: ", variableScoreMG, ":", variableScoreEG)
-		println("################### OTHERS ###################")
-		println("Phase: ", piecePhase)
-		println("!!!--- NOTE: All scores are shown from white's perspective in the debug ---!!!")
-		println("Final score:", score)
+	if tr != nil {
+		tr.Score = score
 	}
 
 	if !b.Wtomove {
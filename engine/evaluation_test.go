@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+func TestThreatDiffs(t *testing.T) {
+	initVariables(nil)
+	// Black's d6 pawn attacks the undefended knight on e5
+	b := gm.ParseFen("4k3/8/3p4/4N3/8/8/8/4K3 w - - 0 1")
+	d := ThreatDiffs(&b)
+	if d[ThreatByPawn] != -1 || d[ThreatHanging] != -1 {
+		t.Errorf("knight attacked by pawn: got diffs %v", d)
+	}
+
+	// Defending the knight removes the hanging threat but not the pawn threat
+	b = gm.ParseFen("4k3/8/3p4/4N3/3P4/8/8/4K3 w - - 0 1")
+	d = ThreatDiffs(&b)
+	if d[ThreatByPawn] != -1 || d[ThreatHanging] != 0 {
+		t.Errorf("defended knight attacked by pawn: got diffs %v", d)
+	}
+}
+
+func TestPassedPawnDiffs(t *testing.T) {
+	initVariables(nil)
+	ClearPawnHash()
+	tests := []struct {
+		fen  string
+		term int
+		want int
+	}{
+		// Rule of the square in a pawn ending
+		{"7k/8/8/8/P7/8/8/K7 b - - 0 1", PassedUnstoppable, 1},
+		{"3k4/8/8/8/P7/8/8/K7 b - - 0 1", PassedUnstoppable, 0},
+		{"7k/8/8/8/P7/8/8/KN6 b - - 0 1", PassedUnstoppable, 0},
+		{"k7/8/8/8/8/p7/8/6K1 w - - 0 1", PassedUnstoppable, -1},
+		// Rook behind the passer, and an enemy piece in between
+		{"4k3/8/8/3P4/8/8/8/3RK3 w - - 0 1", PassedRookBehind, 1},
+		{"4k3/8/8/3P4/3n4/8/8/3RK3 w - - 0 1", PassedRookBehind, 0},
+		// Two connected passers on the fifth rank
+		{"4k3/8/8/2PP4/8/8/8/4K3 w - - 0 1", PassedConnected, 2 * passedRankWeight[4]},
+		{"4k3/8/8/2P1P3/8/8/8/4K3 w - - 0 1", PassedConnected, 0},
+	}
+	for _, tt := range tests {
+		b := gm.ParseFen(tt.fen)
+		if got := PassedPawnDiffs(&b)[tt.term]; got != tt.want {
+			t.Errorf("%s: term %d = %d, want %d", tt.fen, tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestEndgameKingTerms(t *testing.T) {
+	initVariables(nil)
+	// KQK with white winning, and the same position with the colours swapped
+	b := gm.ParseFen("8/8/8/3k4/8/8/8/3QK3 w - - 0 1")
+	_, white := EndgameKingTerms(&b)
+	b = gm.ParseFen("3qk3/8/8/8/3K4/8/8/8 b - - 0 1")
+	_, black := EndgameKingTerms(&b)
+	if white <= 0 || black != -white {
+		t.Errorf("mop-up should be symmetric: white %d, black %d", white, black)
+	}
+
+	// Like evaluation.go, no mop-up while pawns are left
+	b = gm.ParseFen("8/8/8/3k4/8/8/P7/3QK3 w - - 0 1")
+	if _, mop := EndgameKingTerms(&b); mop != 0 {
+		t.Errorf("mop-up with a pawn on the board: got %d, want 0", mop)
+	}
+}
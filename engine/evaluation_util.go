@@ -2,6 +2,7 @@ package engine
 
 import (
 	"math/bits"
+	"unsafe"
 
 	gm "chess-engine/goosemg"
 )
@@ -165,13 +166,13 @@ func getOutpostsBB(b *gm.Board, wPawnAttackBB, bPawnAttackBB uint64) (outposts [
 // PAWN HASH TABLE
 // =============================================================================
 
-const PawnHashSize = 1 << 16 // 65536 entries (~8MB)
+// PawnHashSizeMB is the configured pawn hash table size in MB.
+var PawnHashSizeMB = 16
 
 // PawnHashEntry stores cached pawn structure analysis
 type PawnHashEntry struct {
-	// Key for verifying collisions (pawn bitboards)
-	WhitePawns uint64
-	BlackPawns uint64
+	// Key for verifying collisions (Board.PawnKey)
+	Key uint64
 
 	// Pawn attack maps
 	WPawnAttackBB uint64
@@ -200,6 +201,10 @@ type PawnHashEntry struct {
 	WCandidateBB   uint64
 	BCandidateBB   uint64
 
+	// Rank-weighted connected passer counts (PassedConnected)
+	WPassedConnected int
+	BPassedConnected int
+
 	// Precomputed pawn scores
 	PawnScoreMG int
 	PawnScoreEG int
@@ -207,24 +212,28 @@ type PawnHashEntry struct {
 	Valid bool // flag to mark valid entries
 }
 
-var PawnHashTable [PawnHashSize]PawnHashEntry
+var PawnHashTable = make([]PawnHashEntry, pawnHashEntries(PawnHashSizeMB))
+
+// Probe counters for the hit rate, reset between searches
+var pawnHashProbes, pawnHashHits uint64
+
+func pawnHashEntries(sizeMB int) int {
+	return max(1, sizeMB*1024*1024/int(unsafe.Sizeof(PawnHashEntry{})))
+}
 
-// Compute index into pawn hash table from pawn bitboards (mix bits for distribution)
-func pawnHashIndex(whitePawns, blackPawns uint64) uint64 {
-	const goldenRatio = 0x9E3779B97F4A7C15
-	hash := whitePawns ^ (blackPawns * goldenRatio)
-	hash ^= hash >> 33
-	hash *= 0xFF51AFD7ED558CCD
-	hash ^= hash >> 33
-	return hash & (PawnHashSize - 1)
+// pawnHashIndex maps the pawn key onto the table with a multiply-shift, like
+// the transposition table, so any size can be used.
+func pawnHashIndex(key uint64) uint64 {
+	return (uint64(uint32(key)) * uint64(len(PawnHashTable))) >> 32
 }
 
 // ProbePawnHash returns pawn entry and a hit flag if found
 func ProbePawnHash(b *gm.Board) (*PawnHashEntry, bool) {
-	idx := pawnHashIndex(b.White.Pawns, b.Black.Pawns)
-	entry := &PawnHashTable[idx]
-	if entry.Valid &&
-		entry.WhitePawns == b.White.Pawns && entry.BlackPawns == b.Black.Pawns {
+	key := b.PawnKey()
+	entry := &PawnHashTable[pawnHashIndex(key)]
+	pawnHashProbes++
+	if entry.Valid && entry.Key == key {
+		pawnHashHits++
 		return entry, true
 	}
 	return entry, false
@@ -232,11 +241,29 @@ func ProbePawnHash(b *gm.Board) (*PawnHashEntry, bool) {
 
 // StorePawnHash writes a computed pawn entry to the table
 func StorePawnHash(b *gm.Board, entry *PawnHashEntry) {
-	idx := pawnHashIndex(b.White.Pawns, b.Black.Pawns)
-	entry.WhitePawns = b.White.Pawns
-	entry.BlackPawns = b.Black.Pawns
+	entry.Key = b.PawnKey()
 	entry.Valid = true
-	PawnHashTable[idx] = *entry
+	PawnHashTable[pawnHashIndex(entry.Key)] = *entry
+}
+
+// ResizePawnHash reallocates the pawn hash table to sizeMB megabytes.
+func ResizePawnHash(sizeMB int) {
+	PawnHashSizeMB = sizeMB
+	PawnHashTable = make([]PawnHashEntry, pawnHashEntries(sizeMB))
+}
+
+// PawnHashHitRate returns the share of pawn hash probes that hit since the
+// last search started, per mille.
+func PawnHashHitRate() int {
+	if pawnHashProbes == 0 {
+		return 0
+	}
+	return int(pawnHashHits * 1000 / pawnHashProbes)
+}
+
+// ResetPawnHashStats clears the probe counters.
+func ResetPawnHashStats() {
+	pawnHashProbes, pawnHashHits = 0, 0
 }
 
 // ClearPawnHash resets the pawn hash table (use at start of a new game)
@@ -246,8 +273,9 @@ func ClearPawnHash() {
 	}
 }
 
-// ComputePawnEntry calculates all pawn structure data from scratch (on a cache miss)
-func ComputePawnEntry(b *gm.Board, debug bool) PawnHashEntry {
+// ComputePawnEntry calculates all pawn structure data from scratch (on a cache miss).
+// The pawn terms are recorded into tr when it isn't nil.
+func ComputePawnEntry(b *gm.Board, tr *EvalTrace) PawnHashEntry {
 	var entry PawnHashEntry
 
 	// 1. Pawn attack bitboards
@@ -292,6 +320,8 @@ func ComputePawnEntry(b *gm.Board, debug bool) PawnHashEntry {
 	candidateMG, candidateEG, wCandidate, bCandidate := candidatePassedBonus(b, entry.WPassedBB, entry.BPassedBB, entry.WLeverBB, entry.BLeverBB, entry.WLeverPushedBB, entry.BLeverPushedBB)
 	entry.WCandidateBB = wCandidate
 	entry.BCandidateBB = bCandidate
+	entry.WPassedConnected = connectedPassers(entry.WPassedBB, true)
+	entry.BPassedConnected = connectedPassers(entry.BPassedBB, false)
 	blockedMG, blockedEG := blockedPawnBonus(entry.WBlockedBB, entry.BBlockedBB)
 	backMG, backEG := backwardPawnPenalty(entry.WBackwardBB, entry.BBackwardBB)
 	weakLeverMG, weakLeverEG := pawnWeakLeverPenalty(entry.WWeakLeverBB, entry.BWeakLeverBB)
@@ -300,28 +330,33 @@ func ComputePawnEntry(b *gm.Board, debug bool) PawnHashEntry {
 	entry.PawnScoreMG = pawnPsqtMG + isoMG + doubledMG + connMG + phalMG + passedMG + candidateMG + blockedMG + backMG + weakLeverMG
 	entry.PawnScoreEG = pawnPsqtEG + isoEG + doubledEG + connEG + phalEG + passedEG + candidateEG + blockedEG + backEG + weakLeverEG
 
-	if debug {
-		println("################### PAWN PARAMETERS ###################")
-		println("Pawn MG:\t", "PSQT: ", pawnPsqtMG, "\tIsolated: ", isoMG, "\tDoubled: ", doubledMG,
-			"\tConnected: ", connMG, "\tPhalanx: ", phalMG, "\tPassed: ", passedMG, "\tCandidate: ", candidateMG,
-			"\tBlocked: ", blockedMG, "\tBackward: ", backMG, "\tWeakLever: ", weakLeverMG)
-		println("Pawn EG:\t", "PSQT: ", pawnPsqtEG, "\tIsolated: ", isoEG, "\tDoubled: ", doubledEG,
-			"\tConnected: ", connEG, "\tPhalanx: ", phalEG, "\tPassed: ", passedEG, "\tCandidate: ", candidateEG,
-			"\tBlocked: ", blockedEG, "\tBackward: ", backEG, "\tWeakLever: ", weakLeverEG)
+	if tr != nil {
+		tr.addPsqt("Pawn PSQT", gm.PieceTypePawn, b.White.Pawns, b.Black.Pawns)
+		tr.addSplit("Isolated pawns", isolatedPawnPenalty, entry.WIsolatedBB, entry.BIsolatedBB)
+		tr.add("Doubled pawns", doubledMG, doubledEG)
+		tr.add("Connected pawns", connMG, connEG)
+		tr.add("Phalanx pawns", phalMG, phalEG)
+		tr.addSplit("Passed pawns", passedPawnBonus, entry.WPassedBB, entry.BPassedBB)
+		wCandMG, wCandEG, _, _ := candidatePassedBonus(b, entry.WPassedBB, entry.BPassedBB, entry.WLeverBB, 0, entry.WLeverPushedBB, 0)
+		bCandMG, bCandEG, _, _ := candidatePassedBonus(b, entry.WPassedBB, entry.BPassedBB, 0, entry.BLeverBB, 0, entry.BLeverPushedBB)
+		tr.addSides("Candidate passers", EvalScore{wCandMG, wCandEG}, EvalScore{-bCandMG, -bCandEG}, candidateMG, candidateEG)
+		tr.addSplit("Blocked pawns", blockedPawnBonus, entry.WBlockedBB, entry.BBlockedBB)
+		tr.addSplit("Backward pawns", backwardPawnPenalty, entry.WBackwardBB, entry.BBackwardBB)
+		tr.addSplit("Weak levers", pawnWeakLeverPenalty, entry.WWeakLeverBB, entry.BWeakLeverBB)
 	}
 	return entry
 }
 
 // GetPawnEntry returns a pointer to the pawn hash entry for the current position, computing it if needed.
-func GetPawnEntry(b *gm.Board, debug bool) *PawnHashEntry {
+func GetPawnEntry(b *gm.Board) *PawnHashEntry {
 	entry, hit := ProbePawnHash(b)
 	if hit {
 		return entry
 	}
-	newEntry := ComputePawnEntry(b, debug)
-	StorePawnHash(b, &newEntry)
-	idx := pawnHashIndex(b.White.Pawns, b.Black.Pawns)
-	return &PawnHashTable[idx]
+	*entry = ComputePawnEntry(b, nil)
+	entry.Key = b.PawnKey()
+	entry.Valid = true
+	return entry
 }
 
 func getIsolatedPawnsBitboards(b *gm.Board) (wIsolated uint64, bIsolated uint64) {
@@ -448,8 +483,9 @@ func getPawnLeverBitboards(
 	wLeverPush uint64, bLeverPush uint64,
 	wWeakLever uint64, bWeakLever uint64,
 ) {
-	occ := b.White.All | b.Black.All
-	empty := ^occ
+	// Only pawns block a push: the result is cached in the pawn hash, which
+	// is keyed on the pawns alone.
+	empty := ^(b.White.Pawns | b.Black.Pawns)
 
 	wHitTargets := wPawnAttackBB & b.Black.Pawns
 	wLever = ((wHitTargets &^ bitboardFileH) >> 7) | ((wHitTargets &^ bitboardFileA) >> 9) //&b.White.Pawns
@@ -690,7 +726,7 @@ func calculatePawnSouthFill(pawnBitboard uint64) uint64 {
 	return pawnBitboard
 }
 
-func isTheoreticalDraw(board *gm.Board, debug bool) bool {
+func isTheoreticalDraw(board *gm.Board) bool {
 	pawnCount := bits.OnesCount64(board.White.Pawns | board.Black.Pawns)
 
 	wKnights := bits.OnesCount64(board.White.Knights)
@@ -704,10 +740,6 @@ func isTheoreticalDraw(board *gm.Board, debug bool) bool {
 	bQueens := bits.OnesCount64(board.Black.Queens)
 
 	allPieces := bits.OnesCount64((board.White.All | board.Black.All) & ^(board.White.Kings | board.Black.Kings))
-	if debug {
-		println("All: ", allPieces, "\twQueen: ", wQueens, "\twRooks: ", wRooks, "\twKnights: ", wKnights, "\twBishops: ", wBishops)
-		println("All: ", allPieces, "\tbQueen: ", bQueens, "\tbRooks: ", bRooks, "\tbKnights: ", bKnights, "\tbBishops: ", bBishops)
-	}
 
 	/*
 		GENERAL DRAWS:
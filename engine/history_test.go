@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+func TestCaptHistUpdateAndAge(t *testing.T) {
+	defer CaptHistClear()
+	capture := gm.NewMove(gm.Square(28), gm.Square(35), gm.WhitePawn, gm.BlackKnight, gm.NoPiece, gm.FlagNone)
+	enPassant := gm.NewMove(gm.Square(36), gm.Square(43), gm.WhitePawn, gm.BlackPawn, gm.NoPiece, gm.FlagEnPassant)
+	quiet := gm.NewMove(gm.Square(12), gm.Square(28), gm.WhitePawn, gm.NoPiece, gm.NoPiece, gm.FlagNone)
+
+	for i := 0; i < 1000; i++ {
+		CaptHistUpdateGood(capture, 20)
+		CaptHistUpdateBad(enPassant, 20)
+		CaptHistUpdateGood(quiet, 20)
+	}
+	if got := CaptHistScore(capture); got <= 0 || got > captHistMax {
+		t.Errorf("rewarded capture scored %d", got)
+	}
+	if got := CaptHistScore(enPassant); got >= 0 || got < -captHistMax {
+		t.Errorf("penalized en passant scored %d", got)
+	}
+	if got := CaptHistScore(quiet); got != 0 {
+		t.Errorf("quiet move has capture history %d", got)
+	}
+
+	before := CaptHistScore(capture)
+	CaptHistAge()
+	if got := CaptHistScore(capture); got != before/2 {
+		t.Errorf("aged score %d, want %d", got, before/2)
+	}
+}
@@ -0,0 +1,96 @@
+package engine
+
+import (
+	gm "chess-engine/goosemg"
+)
+
+// =============================================================================
+// MATERIAL HASH TABLE
+// =============================================================================
+// Everything the evaluation derives from the piece counts alone, cached by the
+// board's material key the way PawnHashTable caches pawn structure. The
+// material balance changes only on captures and promotions, so nearly every
+// probe hits.
+
+const MaterialHashSize = 1 << 13 // 8192 entries
+
+// Scale flags: which scale factors endgameScale has to look at
+const (
+	scaleWrongBishopWhite uint8 = 1 << iota // white has a lone bishop and pawns against a bare king
+	scaleWrongBishopBlack
+	scaleOppositeBishops // one bishop each, colors still to be checked
+)
+
+// MaterialHashEntry stores cached material analysis
+type MaterialHashEntry struct {
+	Key uint64 // material key, for verifying collisions
+
+	// Piece values, white minus black
+	MaterialMG int
+	MaterialEG int
+
+	ImbalanceMG int
+	ImbalanceEG int
+
+	Phase int  // GetPiecePhase
+	Draw  bool // isTheoreticalDraw
+
+	scaleFlags uint8
+	endgame    *endgameEntry // dedicated evaluation, nil if there is none
+
+	Valid bool // flag to mark valid entries
+}
+
+var MaterialHashTable [MaterialHashSize]MaterialHashEntry
+
+// ProbeMaterialHash returns the material entry and a hit flag if found
+func ProbeMaterialHash(b *gm.Board) (*MaterialHashEntry, bool) {
+	key := b.MaterialKey()
+	entry := &MaterialHashTable[key&(MaterialHashSize-1)]
+	return entry, entry.Valid && entry.Key == key
+}
+
+// ClearMaterialHash resets the material hash table (use at start of a new game)
+func ClearMaterialHash() {
+	for i := range MaterialHashTable {
+		MaterialHashTable[i] = MaterialHashEntry{}
+	}
+}
+
+// ComputeMaterialEntry calculates the material data of b from scratch (on a cache miss).
+func ComputeMaterialEntry(b *gm.Board) MaterialHashEntry {
+	entry := MaterialHashEntry{Key: b.MaterialKey(), Valid: true}
+
+	wMG, wEG := countMaterial(&b.White)
+	bMG, bEG := countMaterial(&b.Black)
+	entry.MaterialMG = wMG - bMG
+	entry.MaterialEG = wEG - bEG
+	entry.ImbalanceMG, entry.ImbalanceEG = materialImbalance(b)
+	entry.Phase = GetPiecePhase(b)
+	entry.Draw = isTheoreticalDraw(b)
+	entry.endgame = probeEndgame(b)
+
+	wBishops, bBishops := b.PieceCount(gm.WhiteBishop), b.PieceCount(gm.BlackBishop)
+	wPieces := b.PieceCount(gm.WhiteKnight) + wBishops + b.PieceCount(gm.WhiteRook) + b.PieceCount(gm.WhiteQueen)
+	bPieces := b.PieceCount(gm.BlackKnight) + bBishops + b.PieceCount(gm.BlackRook) + b.PieceCount(gm.BlackQueen)
+	wPawns, bPawns := b.PieceCount(gm.WhitePawn), b.PieceCount(gm.BlackPawn)
+	if wPieces == 1 && wBishops == 1 && wPawns > 0 && bPieces == 0 && bPawns == 0 {
+		entry.scaleFlags |= scaleWrongBishopWhite
+	}
+	if bPieces == 1 && bBishops == 1 && bPawns > 0 && wPieces == 0 && wPawns == 0 {
+		entry.scaleFlags |= scaleWrongBishopBlack
+	}
+	if wBishops == 1 && bBishops == 1 {
+		entry.scaleFlags |= scaleOppositeBishops
+	}
+	return entry
+}
+
+// GetMaterialEntry returns a pointer to the material hash entry for the current position, computing it if needed.
+func GetMaterialEntry(b *gm.Board) *MaterialHashEntry {
+	entry, hit := ProbeMaterialHash(b)
+	if !hit {
+		*entry = ComputeMaterialEntry(b)
+	}
+	return entry
+}
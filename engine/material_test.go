@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+func TestMaterialKeyIgnoresPlacement(t *testing.T) {
+	a := gm.ParseFen("4k3/8/8/8/3NB3/8/8/4K3 w - - 0 1")
+	b := gm.ParseFen("7k/8/8/8/8/8/1B4N1/K7 b - - 0 1")
+	c := gm.ParseFen("4k3/8/8/8/3NN3/8/8/4K3 w - - 0 1")
+	if a.MaterialKey() != b.MaterialKey() {
+		t.Errorf("same material, different material keys")
+	}
+	if a.MaterialKey() == c.MaterialKey() {
+		t.Errorf("KBNK and KNNK share a material key")
+	}
+}
+
+func TestMaterialHashMatchesCompute(t *testing.T) {
+	initVariables(nil)
+	ClearMaterialHash()
+	for _, fen := range evalTraceFens {
+		b := gm.ParseFen(fen)
+		// Play through captures and promotions so the incremental key changes
+		for ply := 0; ply < 40; ply++ {
+			want := ComputeMaterialEntry(&b)
+			if got := GetMaterialEntry(&b); *got != want {
+				t.Fatalf("%s: cached entry %+v, computed %+v", b.ToFen(), *got, want)
+			}
+			moves := b.GenerateMoves()
+			if len(moves) == 0 {
+				break
+			}
+			move := moves[0]
+			for _, m := range moves {
+				if m.CapturedPiece() != gm.NoPiece || m.PromotionPieceType() != gm.PieceTypeNone {
+					move = m
+					break
+				}
+			}
+			b.MakeMove(move)
+		}
+	}
+}
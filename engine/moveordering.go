@@ -26,19 +26,23 @@ var mvvLva [7][7]int32 = [7][7]int32{
 	{0, 0, 0, 0, 0, 0, 0},             // victim King
 }
 
+// CaptHistOrderDiv scales capture history into MVV-LVA units; at 32 a
+// saturated entry is worth about two and a half victim classes.
+var CaptHistOrderDiv = 32
+
 var SortingCaptures int
 var SortingNormal int
 
 // Score tiers (from highest to lowest priority):
 // 1. PV/TT move:      2,000,000,000 (MaxInt32 essentially)
 // 2. Queen promo:     1,000,000 + piece value
-// 3. Winning captures: 900,000 + MVV-LVA + SEE bonus
-// 4. Equal captures:   800,000 + MVV-LVA
+// 3. Winning captures: 900,000 + MVV-LVA + capture history + SEE bonus
+// 4. Equal captures:   800,000 + MVV-LVA + capture history
 // 5. Killer 1:         700,000
 // 6. Killer 2:         690,000
 // 7. Counter move:     600,000 + history
 // 8. Quiet moves:      500,000 + history + cont_history (can go negative but still above losing captures)
-// 9. Losing captures:  100,000 + MVV-LVA (still tried, but last)
+// 9. Losing captures:  100,000 + MVV-LVA + capture history (still tried, but last)
 // 10. Under-promos:     50,000 + piece value
 
 const (
@@ -115,84 +119,19 @@ func scoreMovesList(board *gm.Board, moves []gm.Move, _ int8, ply int8, pvMove g
 		mv := moves[i]
 		var moveEval int32
 
-		capturedPiece := mv.CapturedPiece()
-		capturedType := capturedPiece.Type()
-
-		isEnPassant := mv.Flags() == gm.FlagEnPassant
-		if isEnPassant {
-			capturedType = gm.PieceTypePawn
-		}
-		isCapture := capturedPiece != gm.NoPiece || isEnPassant
-
-		promotePiece := mv.PromotionPieceType()
-		isPVMove := (mv == pvMove)
-
-		if isPVMove {
+		if mv == pvMove {
 			// PV/TT move: always searched first
 			moveEval = scorePVMove
-
-		} else if promotePiece != gm.PieceTypeNone {
-			// Promotions: queen promos high, under-promos lower
-			if promotePiece == gm.PieceTypeQueen {
-				moveEval = scoreQueenPromo + int32(pieceValueEG[promotePiece])
-				// If it's also a capture, add MVV bonus
-				if isCapture {
-					moveEval += mvvLva[capturedType][gm.PieceTypePawn]
-				}
-			} else {
-				// Under-promotions (knight, rook, bishop) - rare but sometimes needed
-				moveEval = scoreUnderPromo + int32(pieceValueEG[promotePiece])
-				if isCapture {
-					moveEval += mvvLva[capturedType][gm.PieceTypePawn]
-				}
-			}
-
-		} else if isCapture {
-			pieceTypeFrom := mv.MovedPiece().Type()
-			captureScore := mvvLva[capturedType][pieceTypeFrom]
-
-			victimValue := int(SeePieceValue[capturedType])
-			attackerValue := int(SeePieceValue[pieceTypeFrom])
-
-			if victimValue >= attackerValue {
-				diff := int32(victimValue - attackerValue)
-				moveEval = scoreWinningCapture + captureScore + diff
-
-			} else {
-				// Potentially losing capture - need full SEE
-				seeScore := see(board, mv, false)
-				if seeScore > 0 {
-					// Winning (e.g., protected piece takes unprotected higher piece)
-					moveEval = scoreWinningCapture + captureScore + int32(seeScore)
-				} else if seeScore == 0 {
-					moveEval = scoreEqualCapture + captureScore
-				} else {
-					moveEval = scoreLosingCapture + captureScore
-				}
-			}
-
+		} else if isTacticalMove(mv) {
+			moveEval = scoreTacticalMove(board, mv)
 		} else if SearchState.killer.KillerMoves[killerIdx][0] == mv {
 			// First killer - high priority quiet move
 			moveEval = scoreKiller1
-
 		} else if SearchState.killer.KillerMoves[killerIdx][1] == mv {
 			// Second killer
 			moveEval = scoreKiller2
-
 		} else {
-			// Regular quiet move: combine main history + continuation history
-			histScore := int32(SearchState.historyMoves[side][mv.From()][mv.To()])
-
-			// NEW: Add continuation history score (weighted at 50%)
-			contScore := int32(ContHistScore(side, mv, prev1Ply, prev2Ply))
-			combinedHist := histScore + contScore/2
-
-			moveEval = scoreQuietBase + combinedHist
-
-			// Counter move bonus (still uses combined history for tie-breaking)
-			if prevMove != 0 && SearchState.counterMoves[side][prevMove.From()][prevMove.To()] == mv {
-				moveEval = scoreCounterMove + combinedHist
-			}
+			moveEval = scoreQuietMove(mv, side, prev1Ply, prev2Ply, prevMove)
 		}
 
 		movesList.moves[i].move = mv
@@ -202,6 +141,78 @@ func scoreMovesList(board *gm.Board, moves []gm.Move, _ int8, ply int8, pvMove g
 	return movesList
 }
 
+// isTacticalMove reports whether mv is a capture (including en passant) or a
+// promotion, judged from the move encoding alone.
+func isTacticalMove(mv gm.Move) bool {
+	return mv.CapturedPiece() != gm.NoPiece || mv.Flags() == gm.FlagEnPassant || mv.PromotionPieceType() != gm.PieceTypeNone
+}
+
+// scoreTacticalMove scores a capture or promotion: queen promotions first,
+// then captures by MVV-LVA with SEE deciding between the winning, equal and
+// losing tiers, and under-promotions last.
+func scoreTacticalMove(board *gm.Board, mv gm.Move) int32 {
+	capturedType := mv.CapturedPiece().Type()
+	isEnPassant := mv.Flags() == gm.FlagEnPassant
+	if isEnPassant {
+		capturedType = gm.PieceTypePawn
+	}
+	isCapture := capturedType != gm.PieceTypeNone
+
+	promotePiece := mv.PromotionPieceType()
+	if promotePiece != gm.PieceTypeNone {
+		// Promotions: queen promos high, under-promos lower
+		var moveEval int32
+		if promotePiece == gm.PieceTypeQueen {
+			moveEval = scoreQueenPromo + int32(pieceValueEG[promotePiece])
+		} else {
+			// Under-promotions (knight, rook, bishop) - rare but sometimes needed
+			moveEval = scoreUnderPromo + int32(pieceValueEG[promotePiece])
+		}
+		// If it's also a capture, add MVV bonus
+		if isCapture {
+			moveEval += mvvLva[capturedType][gm.PieceTypePawn]
+		}
+		return moveEval
+	}
+
+	pieceTypeFrom := mv.MovedPiece().Type()
+	captureScore := mvvLva[capturedType][pieceTypeFrom] + int32(CaptHistScore(mv)/CaptHistOrderDiv)
+
+	victimValue := int(SeePieceValue[capturedType])
+	attackerValue := int(SeePieceValue[pieceTypeFrom])
+
+	if victimValue >= attackerValue {
+		diff := int32(victimValue - attackerValue)
+		return scoreWinningCapture + captureScore + diff
+	}
+
+	// Potentially losing capture - need full SEE
+	seeScore := see(board, mv, false)
+	if seeScore > 0 {
+		// Winning (e.g., protected piece takes unprotected higher piece)
+		return scoreWinningCapture + captureScore + int32(seeScore)
+	} else if seeScore == 0 {
+		return scoreEqualCapture + captureScore
+	}
+	return scoreLosingCapture + captureScore
+}
+
+// scoreQuietMove scores a non-capturing move by main plus continuation
+// history, lifting the counter move to its own tier.
+func scoreQuietMove(mv gm.Move, side int, prev1Ply, prev2Ply ContHistEntry, prevMove gm.Move) int32 {
+	histScore := int32(SearchState.historyMoves[side][mv.From()][mv.To()])
+
+	// Continuation history weighted at 50%
+	contScore := int32(ContHistScore(side, mv, prev1Ply, prev2Ply))
+	combinedHist := histScore + contScore/2
+
+	// Counter move bonus (still uses combined history for tie-breaking)
+	if prevMove != 0 && SearchState.counterMoves[side][prevMove.From()][prevMove.To()] == mv {
+		return scoreCounterMove + combinedHist
+	}
+	return scoreQuietBase + combinedHist
+}
+
 func scoreMovesListCaptures(moves []gm.Move, ply int8) (movesList moveList, anyCaptures bool) {
 	if ply < 0 {
 		ply = 0
@@ -224,7 +235,7 @@ func scoreMovesListCaptures(moves []gm.Move, ply int8) (movesList moveList, anyC
 
 		if capturedPiece != gm.NoPiece || mv.Flags() == gm.FlagEnPassant {
 			moverType := mv.MovedPiece().Type()
-			score := mvvLva[capturedType][moverType]
+			score := mvvLva[capturedType][moverType] + int32(CaptHistScore(mv)/CaptHistOrderDiv)
 
 			pool[capturedMovesIndex].move = mv
 			pool[capturedMovesIndex].score = score
@@ -0,0 +1,189 @@
+package engine
+
+import (
+	gm "chess-engine/goosemg"
+)
+
+// pickStage is the phase a movePicker is in. Moves are generated lazily, so
+// a node that cuts on the TT move or an early capture never generates quiets.
+type pickStage uint8
+
+const (
+	stageTTMove pickStage = iota
+	stageGenCaptures
+	stageGoodCaptures
+	stageKiller1
+	stageKiller2
+	stageCounterMove
+	stageGenQuiets
+	stageQuiets
+	stageBadCaptures
+	stageDone
+)
+
+// Per-ply buffers for the picker; a node's picker only lives during its move
+// loop, and every recursive search inside that loop runs at ply+1.
+var genMovePool [MaxPlyMoveList][MaxMovesPerPosition]gm.Move
+var captureListPool [MaxPlyMoveList][MaxMovesPerPosition]move
+
+// movePicker yields the moves of a node in stages:
+//  1. the TT move, if it is pseudo-legal and legal here
+//  2. captures that don't lose material, in MVV-LVA/SEE order
+//  3. killer 1, killer 2 and the counter move, each validated on this board
+//  4. remaining quiets by history (quiet queen promotions first)
+//  5. losing captures and capturing under-promotions
+//
+// Generated captures and quiets come from the legal generators; only the
+// moves carried over from other positions need the legality check.
+type movePicker struct {
+	board    *gm.Board
+	ply      int8
+	side     int
+	prevMove gm.Move
+	stage    pickStage
+
+	ttMove  gm.Move
+	killers [2]gm.Move
+	counter gm.Move
+
+	captures moveList
+	quiets   moveList
+	current  int
+	badEnd   int // captures[:badEnd] holds the losing captures set aside
+}
+
+func newMovePicker(b *gm.Board, ply int8, ttMove gm.Move, prevMove gm.Move) movePicker {
+	if ply < 0 {
+		ply = 0
+	}
+	if int(ply) >= MaxPlyMoveList {
+		ply = MaxPlyMoveList - 1
+	}
+	side := 0
+	if !b.Wtomove {
+		side = 1
+	}
+
+	killerIdx := Min(int(ply), len(SearchState.killer.KillerMoves)-1)
+	var counter gm.Move
+	if prevMove != 0 {
+		counter = SearchState.counterMoves[side][prevMove.From()][prevMove.To()]
+	}
+
+	return movePicker{
+		board:    b,
+		ply:      ply,
+		side:     side,
+		prevMove: prevMove,
+		ttMove:   ttMove,
+		killers:  SearchState.killer.KillerMoves[killerIdx],
+		counter:  counter,
+	}
+}
+
+// next returns the next move to try, or 0 when the node is exhausted.
+func (mp *movePicker) next() gm.Move {
+	for {
+		switch mp.stage {
+		case stageTTMove:
+			mp.stage++
+			if mp.ttMove != 0 && mp.board.IsPseudoLegal(mp.ttMove) && mp.board.IsLegal(mp.ttMove) {
+				return mp.ttMove
+			}
+			mp.ttMove = 0
+
+		case stageGenCaptures:
+			generated := mp.board.GenerateCapturesInto(genMovePool[mp.ply][:0])
+			mp.captures.moves = captureListPool[mp.ply][:len(generated)]
+			for i, mv := range generated {
+				mp.captures.moves[i] = move{move: mv, score: scoreTacticalMove(mp.board, mv)}
+			}
+			mp.current, mp.badEnd = 0, 0
+			mp.stage++
+
+		case stageGoodCaptures:
+			for mp.current < len(mp.captures.moves) {
+				orderNextMove(uint8(mp.current), &mp.captures)
+				entry := mp.captures.moves[mp.current]
+				mp.current++
+				if entry.move == mp.ttMove {
+					continue
+				}
+				if entry.score < scoreEqualCapture {
+					// Losing capture or under-promotion: revisit after the quiets.
+					// Slots before current are already consumed, so this is safe.
+					mp.captures.moves[mp.badEnd] = entry
+					mp.badEnd++
+					continue
+				}
+				return entry.move
+			}
+			mp.stage++
+
+		case stageKiller1, stageKiller2, stageCounterMove:
+			var slot *gm.Move
+			switch mp.stage {
+			case stageKiller1:
+				slot = &mp.killers[0]
+			case stageKiller2:
+				slot = &mp.killers[1]
+				if *slot == mp.killers[0] {
+					*slot = 0
+				}
+			default:
+				slot = &mp.counter
+				if *slot == mp.killers[0] || *slot == mp.killers[1] {
+					*slot = 0
+				}
+			}
+			mp.stage++
+			candidate := *slot
+			if candidate != 0 && candidate != mp.ttMove && !isTacticalMove(candidate) &&
+				mp.board.IsPseudoLegal(candidate) && mp.board.IsLegal(candidate) {
+				return candidate
+			}
+			// Not played here, so the quiet stage must not skip it either
+			*slot = 0
+
+		case stageGenQuiets:
+			generated := mp.board.GenerateQuietsInto(genMovePool[mp.ply][:0])
+			mp.quiets.moves = moveListPool[mp.ply][:len(generated)]
+			prev1Ply, prev2Ply := SearchState.ContHistContext(mp.ply)
+			for i, mv := range generated {
+				var score int32
+				if mv.PromotionPieceType() != gm.PieceTypeNone {
+					score = scoreTacticalMove(mp.board, mv)
+				} else {
+					score = scoreQuietMove(mv, mp.side, prev1Ply, prev2Ply, mp.prevMove)
+				}
+				mp.quiets.moves[i] = move{move: mv, score: score}
+			}
+			mp.current = 0
+			mp.stage++
+
+		case stageQuiets:
+			for mp.current < len(mp.quiets.moves) {
+				orderNextMove(uint8(mp.current), &mp.quiets)
+				mv := mp.quiets.moves[mp.current].move
+				mp.current++
+				if mv == mp.ttMove || mv == mp.killers[0] || mv == mp.killers[1] || mv == mp.counter {
+					continue
+				}
+				return mv
+			}
+			mp.current = 0
+			mp.stage++
+
+		case stageBadCaptures:
+			if mp.current < mp.badEnd {
+				mv := mp.captures.moves[mp.current].move
+				mp.current++
+				return mv
+			}
+			mp.stage++
+
+		default:
+			return 0
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"math/rand"
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+var pickerFENs = []string{
+	gm.FENStartPos,
+	"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+	"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+	"r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1",
+	"rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+	"4k3/8/8/2KpP3/8/8/8/8 w - d6 0 2",
+	"4k3/8/8/8/8/8/8/R3K2r w Q - 0 1", // in check
+}
+
+// TestMovePickerYieldsLegalMovesOnce feeds the picker TT, killer and counter
+// moves taken from other positions and checks that it still yields exactly
+// the legal move set, each move once.
+func TestMovePickerYieldsLegalMovesOnce(t *testing.T) {
+	var foreign []gm.Move
+	for _, fen := range pickerFENs {
+		b := gm.ParseFen(fen)
+		foreign = append(foreign, b.GenerateLegalMoves()...)
+	}
+	defer func() {
+		SearchState.killer = KillerStruct{}
+		SearchState.counterMoves = [2][64][64]gm.Move{}
+	}()
+
+	for _, fen := range pickerFENs {
+		b := gm.ParseFen(fen)
+		legal := b.GenerateLegalMoves()
+		candidates := append(append([]gm.Move{0}, legal...), foreign...)
+
+		for i := 0; i < len(candidates); i += 7 {
+			ttMove := candidates[i]
+			SearchState.killer.KillerMoves[3][0] = candidates[(i+1)%len(candidates)]
+			SearchState.killer.KillerMoves[3][1] = candidates[(i+2)%len(candidates)]
+			prevMove := legal[0]
+			SearchState.counterMoves[0][prevMove.From()][prevMove.To()] = candidates[(i+3)%len(candidates)]
+			SearchState.counterMoves[1][prevMove.From()][prevMove.To()] = candidates[(i+3)%len(candidates)]
+
+			seen := make(map[gm.Move]int)
+			picker := newMovePicker(&b, 3, ttMove, prevMove)
+			first := gm.Move(0)
+			for mv := picker.next(); mv != 0; mv = picker.next() {
+				if first == 0 {
+					first = mv
+				}
+				seen[mv]++
+			}
+			for _, mv := range legal {
+				if seen[mv] != 1 {
+					t.Fatalf("%s tt=%v: move %v yielded %d times", fen, ttMove, mv, seen[mv])
+				}
+			}
+			if len(seen) != len(legal) {
+				t.Fatalf("%s tt=%v: yielded %d distinct moves, want %d", fen, ttMove, len(seen), len(legal))
+			}
+			if seen[ttMove] == 1 && first != ttMove {
+				t.Errorf("%s: legal TT move %v was not yielded first (got %v)", fen, ttMove, first)
+			}
+		}
+	}
+}
+
+// pickerOrderKey maps the score the full-list ordering gives a move to the
+// place the picker tries it. The picker keeps that order except for quiet
+// promotions, which it generates with the quiets: queen promotions lead the
+// quiets instead of coming before the captures, and under-promotions close
+// them instead of coming after the losing captures.
+func pickerOrderKey(mv gm.Move, score int32) int32 {
+	if score == scorePVMove || mv.CapturedPiece() != gm.NoPiece {
+		return score
+	}
+	switch mv.PromotionPieceType() {
+	case gm.PieceTypeNone:
+		return score
+	case gm.PieceTypeQueen:
+		return (scoreCounterMove+scoreQuietBase)/2 + score - scoreQueenPromo
+	default:
+		return (scoreQuietBase+scoreLosingCapture)/2 + score - scoreUnderPromo
+	}
+}
+
+// TestMovePickerFollowsScoredOrder checks the picker against scoreMovesList
+// over the full legal list, the ordering it replaced: apart from the quiet
+// promotions, moves only change places within a tie.
+func TestMovePickerFollowsScoredOrder(t *testing.T) {
+	defer func() {
+		SearchState.killer = KillerStruct{}
+		SearchState.counterMoves = [2][64][64]gm.Move{}
+		SearchState.historyMoves = [2][64][64]int{}
+	}()
+	rng := rand.New(rand.NewSource(31))
+	for side := range SearchState.historyMoves {
+		for from := range SearchState.historyMoves[side] {
+			for to := range SearchState.historyMoves[side][from] {
+				SearchState.historyMoves[side][from][to] = rng.Intn(2*historyMaxVal+1) - historyMaxVal
+			}
+		}
+	}
+
+	fens := append([]string{
+		"3k4/1P6/8/8/8/8/8/4K3 w - - 0 1",
+		"r3k3/1P6/8/8/8/8/8/4K3 w - - 0 1",
+	}, pickerFENs...)
+	for _, b := range symmetryPositions(300) {
+		fens = append(fens, b.ToFen())
+	}
+
+	const ply = 3
+	for _, fen := range fens {
+		b := gm.ParseFen(fen)
+		legal := b.GenerateLegalMoves()
+		if len(legal) == 0 {
+			continue
+		}
+		pick := func() gm.Move {
+			if rng.Intn(3) == 0 {
+				return 0
+			}
+			return legal[rng.Intn(len(legal))]
+		}
+		ttMove := pick()
+		SearchState.killer.KillerMoves[ply][0] = pick()
+		SearchState.killer.KillerMoves[ply][1] = pick()
+		prevMove := legal[0]
+		SearchState.counterMoves[0][prevMove.From()][prevMove.To()] = pick()
+		SearchState.counterMoves[1][prevMove.From()][prevMove.To()] = pick()
+
+		// The scored list shares its buffer with the picker's quiets
+		keys := make(map[gm.Move]int32, len(legal))
+		for _, m := range scoreMovesList(&b, legal, 0, ply, ttMove, prevMove).moves {
+			keys[m.move] = pickerOrderKey(m.move, m.score)
+		}
+
+		picker := newMovePicker(&b, ply, ttMove, prevMove)
+		var last gm.Move
+		for mv := picker.next(); mv != 0; mv = picker.next() {
+			if last != 0 && keys[mv] > keys[last] {
+				t.Errorf("%s: %v (key %d) tried after %v (key %d)", fen, mv, keys[mv], last, keys[last])
+				break
+			}
+			last = mv
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package engine
+
+import (
+	gm "chess-engine/goosemg"
+)
+
+// The board keeps material + PSQT as running sums (see goosemg/psqt.go); the
+// engine owns the values, so it builds the combined tables and installs them
+// before any board is parsed. Pawns contribute only their material: the pawn
+// PSQT is part of the pawn hash score.
+func init() {
+	installPSQTables()
+}
+
+// installPSQTables rebuilds the board's material + PSQT tables from
+// pieceValueMG/EG and PSQT_MG/EG. Call it again after changing those.
+func installPSQTables() {
+	t := &gm.PSQTables{}
+	for pt := gm.PieceTypePawn; pt <= gm.PieceTypeKing; pt++ {
+		white := gm.PieceFromType(gm.White, pt)
+		black := gm.PieceFromType(gm.Black, pt)
+		for sq := 0; sq < 64; sq++ {
+			mg, eg := pieceValueMG[pt], pieceValueEG[pt]
+			blackMG, blackEG := mg, eg
+			if pt != gm.PieceTypePawn {
+				mg, eg = mg+PSQT_MG[pt][sq], eg+PSQT_EG[pt][sq]
+				blackMG, blackEG = blackMG+PSQT_MG[pt][FlipView[sq]], blackEG+PSQT_EG[pt][FlipView[sq]]
+			}
+			t.MG[white][sq], t.EG[white][sq] = int32(mg), int32(eg)
+			t.MG[black][sq], t.EG[black][sq] = -int32(blackMG), -int32(blackEG)
+		}
+	}
+	gm.SetPSQTables(t)
+}
+
+// psqtScore returns material plus the piece-square tables of every piece but
+// the pawns, white minus black: the board's running sums when it has them,
+// otherwise a full count.
+func psqtScore(b *gm.Board) (mg, eg int) {
+	if accMG, accEG, ok := b.PSQT(); ok {
+		return int(accMG), int(accEG)
+	}
+	return countPSQT(b)
+}
+
+// countPSQT computes psqtScore from the bitboards.
+func countPSQT(b *gm.Board) (mg, eg int) {
+	wMG, wEG := countMaterial(&b.White)
+	bMG, bEG := countMaterial(&b.Black)
+	mg, eg = wMG-bMG, wEG-bEG
+	pieces := [...]struct {
+		pt           gm.PieceType
+		white, black *uint64
+	}{
+		{gm.PieceTypeKnight, &b.White.Knights, &b.Black.Knights},
+		{gm.PieceTypeBishop, &b.White.Bishops, &b.Black.Bishops},
+		{gm.PieceTypeRook, &b.White.Rooks, &b.Black.Rooks},
+		{gm.PieceTypeQueen, &b.White.Queens, &b.Black.Queens},
+		{gm.PieceTypeKing, &b.White.Kings, &b.Black.Kings},
+	}
+	for _, p := range pieces {
+		psqtMG, psqtEG := countPieceTables(p.white, p.black, &PSQT_MG[p.pt], &PSQT_EG[p.pt])
+		mg += psqtMG
+		eg += psqtEG
+	}
+	return mg, eg
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+// walkPSQT checks the running sums after every move of a depth-limited tree,
+// which covers captures, en passant, promotions, castling and the undo path.
+func walkPSQT(t *testing.T, b *gm.Board, depth int) {
+	t.Helper()
+	mg, eg, ok := b.PSQT()
+	if !ok {
+		t.Fatalf("%s: board has no running PSQT sums", b.ToFen())
+	}
+	if wantMG, wantEG := countPSQT(b); int(mg) != wantMG || int(eg) != wantEG {
+		t.Fatalf("%s: running sums (%d, %d), recount (%d, %d)", b.ToFen(), mg, eg, wantMG, wantEG)
+	}
+	if depth == 0 {
+		return
+	}
+	for _, m := range b.GenerateMoves() {
+		undo := b.Apply(m)
+		walkPSQT(t, b, depth-1)
+		undo()
+	}
+}
+
+func TestPSQTAccumulatorsMatchRecount(t *testing.T) {
+	gm.VerifyPSQT = true
+	defer func() { gm.VerifyPSQT = false }()
+
+	fens := append([]string{
+		"8/P1k5/8/8/8/8/5Kp1/8 w - - 0 1",                               // promotions
+		"rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3", // en passant
+	}, evalTraceFens...)
+	for _, fen := range fens {
+		b := gm.ParseFen(fen)
+		walkPSQT(t, &b, 3)
+	}
+}
+
+func TestPSQTFallsBackWithoutRunningSums(t *testing.T) {
+	b := gm.ParseFen(evalTraceFens[2])
+	wantMG, wantEG := psqtScore(&b)
+
+	// A board assembled piece by piece was never bound to the tables.
+	var c gm.Board
+	for sq := gm.Square(0); sq < 64; sq++ {
+		if p := b.PieceAt(sq); p != gm.NoPiece {
+			c.SetPiece(sq, p)
+		}
+	}
+	c.White, c.Black = c.WhiteBitboards(), c.BlackBitboards()
+	if _, _, ok := c.PSQT(); ok {
+		t.Fatalf("zero board reports running PSQT sums")
+	}
+	if mg, eg := psqtScore(&c); mg != wantMG || eg != wantEG {
+		t.Errorf("fallback (%d, %d), running sums (%d, %d)", mg, eg, wantMG, wantEG)
+	}
+	c.RefreshPSQT()
+	if mg, eg, ok := c.PSQT(); !ok || int(mg) != wantMG || int(eg) != wantEG {
+		t.Errorf("after RefreshPSQT (%d, %d, %v), want (%d, %d)", mg, eg, ok, wantMG, wantEG)
+	}
+}
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	gm "chess-engine/goosemg"
@@ -24,7 +25,17 @@ var FutilityScale int32 = 114
 var RFPScale int32 = 83
 var RazoringScale int32 = 155
 
+// Contempt is how much the engine dislikes a draw, in centipawns with all
+// pieces on the board (UCI option Contempt). Positive values avoid draws.
+var Contempt int32 = 0
+
+// AnalyseMode is UCI_AnalyseMode: analysis scores draws as DrawScore for both
+// sides, whatever Contempt is set to.
+var AnalyseMode = false
+
 var AspirationWindowSize int32 = 40
+var AspirationMinDepth uint8 = 4   // shallower iterations search the full window
+var AspirationMaxDelta int32 = 800 // beyond this a failing window opens fully
 
 // =============================================================================
 // LMR PARAMETERS
@@ -45,12 +56,15 @@ var NMMarginDepth int32 = 16
 var ProbCutSeeMargin int = 140
 var DeltaMargin int32 = 210
 var QuiescenceSeeMargin int = 150
+var SeeCaptureMargin int = 100
+var CaptHistSeeDiv = 64
 
 func StartSearch(board *gm.Board, depth uint8, gameTime int, increment int, movesToGo int, useCustomDepth bool, evalOnly bool, moveOrderingOnly bool, printSearchInformation bool) string {
 	initVariables(board)
 
 	//Stat reset
 	SearchState.ResetForSearch(board)
+	SearchState.setContempt(board)
 
 	if !SearchState.tt.isInitialized {
 		SearchState.tt.init()
@@ -59,12 +73,13 @@ func StartSearch(board *gm.Board, depth uint8, gameTime int, increment int, move
 	SearchState.GlobalStop = false
 	SearchState.timeHandler.initTimemanagement(gameTime, increment, board.FullmoveNumber(), movesToGo, useCustomDepth)
 	SearchState.timeHandler.StartTime(board.FullmoveNumber())
+	SearchState.timeHandler.startNodeBudget(SearchState.nodesChecked)
 
 	var bestMove gm.Move
 
 	if evalOnly {
-		Evaluation(board, true)
-		println("Is this a theoretical draw: ", isTheoreticalDraw(board, true))
+		trace := EvaluateWithTrace(board)
+		trace.WriteTable(os.Stdout)
 		return ""
 	}
 
@@ -89,12 +104,7 @@ func rootsearch(b *gm.Board, depth uint8, useCustomDepth bool, printSearchInform
 	var beta int32 = MaxScore
 	var bestScore int32 = -MaxScore
 	rootIndex := len(SearchState.stateStack) - 1
-	var aspCounter = 0
-
-	if SearchState.prevSearchScore != 0 {
-		alpha = SearchState.prevSearchScore - AspirationWindowSize
-		beta = SearchState.prevSearchScore + AspirationWindowSize
-	}
+	var delta = AspirationWindowSize
 
 	var nullMove gm.Move
 	var bestMove gm.Move
@@ -102,7 +112,12 @@ func rootsearch(b *gm.Board, depth uint8, useCustomDepth bool, printSearchInform
 	var prevPVLine PVLine
 	var mateFound bool
 
+	SearchState.printInfo = printSearchInformation
+
 	for i := uint8(1); i <= depth; i++ {
+		if i > 1 && SearchState.timeHandler.SoftNodesExceeded(SearchState.nodesChecked) {
+			break
+		}
 		if !useCustomDepth && i > 1 {
 			if SearchState.timeHandler.SoftTimeExceeded() && !SearchState.timeHandler.ShouldExtendTime() {
 				break
@@ -114,6 +129,7 @@ func rootsearch(b *gm.Board, depth uint8, useCustomDepth bool, printSearchInform
 
 		pvLine.Clear()
 		mateFound = false
+		SearchState.selDepth = 0
 
 		startTime := time.Now()
 		score := alphabeta(b, alpha, beta, int8(i), 0, &pvLine, nullMove, false, false, 0, rootIndex)
@@ -128,18 +144,28 @@ func rootsearch(b *gm.Board, depth uint8, useCustomDepth bool, printSearchInform
 			break
 		}
 
-		if timeSpent == 0 {
-			timeSpent = 1
-		}
-		nps := uint64(float64(SearchState.nodesChecked*1000) / float64(timeSpent))
-
-		theMoves := getPVLineString(pvLine)
-
 		if score <= alpha || score >= beta {
-			aspCounter++
-			// Immediately open to full window and retry
-			alpha = -MaxScore
-			beta = MaxScore
+			if printSearchInformation {
+				bound := "lowerbound"
+				if score <= alpha {
+					bound = "upperbound"
+				}
+				printIterationInfo(i, score, bound, timeSpent, pvLine)
+			}
+			// Widen only the side that failed, by a step that grows with every
+			// retry. A fail low also pulls beta to the middle of the old window
+			// so the re-search doesn't stay biased upwards.
+			delta += delta / 2
+			if score <= alpha {
+				beta = (alpha + beta) / 2
+				alpha = Max32(score-delta, -MaxScore)
+			} else {
+				beta = Min32(score+delta, MaxScore)
+			}
+			if delta > AspirationMaxDelta {
+				alpha = -MaxScore
+				beta = MaxScore
+			}
 			i--
 			continue
 		}
@@ -148,8 +174,15 @@ func rootsearch(b *gm.Board, depth uint8, useCustomDepth bool, printSearchInform
 			mateFound = true
 		}
 
-		alpha = score - AspirationWindowSize
-		beta = score + AspirationWindowSize
+		// Aspiration window around this score for the next depth
+		delta = AspirationWindowSize
+		if i+1 >= AspirationMinDepth && abs32(score) < Checkmate {
+			alpha = Max32(score-delta, -MaxScore)
+			beta = Min32(score+delta, MaxScore)
+		} else {
+			alpha = -MaxScore
+			beta = MaxScore
+		}
 		bestScore = score
 
 		if len(pvLine.Moves) > 0 {
@@ -164,14 +197,7 @@ func rootsearch(b *gm.Board, depth uint8, useCustomDepth bool, printSearchInform
 		prevPVLine = pvLine.Clone()
 
 		if printSearchInformation {
-			fmt.Println(
-				"info depth", i,
-				"score", getMateOrCPScore(int(score)),
-				"nodes", SearchState.nodesChecked,
-				"time", timeSpent,
-				"nps", nps,
-				"pv", theMoves,
-			)
+			printIterationInfo(i, score, "", timeSpent, pvLine)
 		}
 
 		if mateFound {
@@ -190,8 +216,54 @@ func rootsearch(b *gm.Board, depth uint8, useCustomDepth bool, printSearchInform
 	return int(bestScore), bestMove
 }
 
+// printIterationInfo prints the UCI info line of a root search. bound is
+// "lowerbound" or "upperbound" when the score fell outside the aspiration
+// window, empty for an exact score.
+func printIterationInfo(depth uint8, score int32, bound string, timeSpent int64, pvLine PVLine) {
+	if timeSpent == 0 {
+		timeSpent = 1
+	}
+	nps := uint64(float64(SearchState.nodesChecked*1000) / float64(timeSpent))
+	scoreStr := getMateOrCPScore(int(score))
+	if bound != "" {
+		scoreStr += " " + bound
+	}
+	info := []any{
+		"info depth", depth,
+		"seldepth", SearchState.selDepth,
+		"score", scoreStr,
+		"nodes", SearchState.nodesChecked,
+		"time", timeSpent,
+		"nps", nps,
+		"hashfull", SearchState.tt.GetHashfull(),
+		"tbhits", 0, // no tablebase probing yet
+	}
+	// A failed aspiration search may not have a PV yet
+	if len(pvLine.Moves) > 0 {
+		info = append(info, "pv", getPVLineString(pvLine))
+	}
+	fmt.Println(info...)
+}
+
+// alphabeta searches a node, recording it when the search tracer is on.
 func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLine *PVLine, prevMove gm.Move, didNull bool, isExtended bool, excludedMove gm.Move, rootIndex int) int32 {
+	if !SearchState.tracer.enabled {
+		return alphabetaNode(b, alpha, beta, depth, ply, pvLine, prevMove, didNull, isExtended, excludedMove, rootIndex)
+	}
+	SearchState.tracer.begin(TraceAlphaBeta, depth, ply, alpha, beta)
+	score := alphabetaNode(b, alpha, beta, depth, ply, pvLine, prevMove, didNull, isExtended, excludedMove, rootIndex)
+	SearchState.tracer.end(score)
+	return score
+}
+
+func alphabetaNode(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLine *PVLine, prevMove gm.Move, didNull bool, isExtended bool, excludedMove gm.Move, rootIndex int) int32 {
 	SearchState.nodesChecked++
+	if ply > SearchState.selDepth {
+		SearchState.selDepth = ply
+	}
+	if SearchState.timeHandler.NodeLimitReached(SearchState.nodesChecked) {
+		SearchState.searchShouldStop = true
+	}
 
 	if SearchState.nodesChecked&4095 == 0 {
 		if SearchState.timeHandler.TimeStatus() {
@@ -200,7 +272,7 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 	}
 
 	if ply >= MaxDepth {
-		return Evaluation(b, false)
+		return SearchState.contemptEval(b, SearchState.staticEval(b))
 	}
 
 	if SearchState.ShouldStopNoClock() {
@@ -214,23 +286,22 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 	var isRoot = ply == 0
 
 	if !isRoot {
+		drawScore := SearchState.drawScore(b)
 		if SearchState.isDraw(int(ply), rootIndex) {
-			return DrawScore
+			return drawScore
 		}
-		if alpha < DrawScore && SearchState.upcomingRepetition(int(ply), rootIndex) {
-			alpha = DrawScore
+		if alpha < drawScore && SearchState.upcomingRepetition(int(ply), rootIndex) {
+			alpha = drawScore
 		}
 	}
 
 	inCheck := b.OurKingInCheck()
-	allMoves := b.GenerateLegalMoves()
-	hasNoLegalMoves := len(allMoves) == 0
 
-	// Draw or checkmate ...
-	if !inCheck && hasNoLegalMoves {
-		return DrawScore
-	} else if inCheck && hasNoLegalMoves {
-		return -MaxScore + int32(ply)
+	// The move loop finds mates and stalemates only after pruning and the
+	// quiescence search, which would score a stalemate from the eval. Nothing
+	// is pruned in check, so only stalemate needs catching here.
+	if !inCheck && !b.HasLegalMoves() {
+		return SearchState.drawScore(b)
 	}
 
 	// Check extension
@@ -242,7 +313,7 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 		return quiescence(b, alpha, beta, pvLine, 30, ply, rootIndex)
 	}
 
-	posHash := b.Hash()
+	posHash := b.Hash() ^ SearchState.ttKeySalt
 
 	/*
 		====== TRANSPOSITION TABLE ======
@@ -255,10 +326,16 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 
 	if usable && !isRoot && !isPVNode {
 		SearchState.cutStats.TTCutoffs++
+		SearchState.tracer.tt(TTCut)
+		SearchState.tracer.prune(PruneTT)
 		return ttScore
 	}
+	if ttHit {
+		SearchState.tracer.tt(TTHit)
+	} else {
+		SearchState.tracer.tt(TTMiss)
+	}
 
-	var staticScore int32
 	var ttMove gm.Move
 	if ttHit {
 		ttMove = ttEntry.Move
@@ -268,21 +345,44 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 		bestMove = ttMove
 	}
 
-	staticScore = Evaluation(b, false)
+	// The TT keeps the static eval of every searched node, so a hit saves
+	// the evaluation even when the entry is too shallow to cut
+	var rawEval int32
+	if ttHit && ttEntry.Eval != NoEval {
+		rawEval = ttEntry.Eval
+	} else {
+		rawEval = SearchState.staticEval(b)
+	}
+
+	// Correction history shifts the eval by the error search has seen in
+	// positions with the same pawns and pieces. The TT keeps the raw eval,
+	// since the correction keeps changing.
+	eval := SearchState.nodeEval(b, rawEval, inCheck)
+
+	// For pruning, a TT bound on the right side of the eval is a better
+	// estimate than the eval itself
+	staticScore := eval
+	if ttHit && abs32(ttEntry.Score) < Checkmate {
+		switch {
+		case ttEntry.Flag == ExactFlag,
+			ttEntry.Flag == BetaFlag && ttEntry.Score > staticScore,
+			ttEntry.Flag == AlphaFlag && ttEntry.Score < staticScore:
+			staticScore = ttEntry.Score
+		}
+	}
 
-	// If we're
 	// Store eval (with invalid marker for check positions)
 	if inCheck {
 		SearchState.evalStack[ply] = -MaxScore // We never aggressively prune checks
 	} else {
-		SearchState.evalStack[ply] = staticScore
+		SearchState.evalStack[ply] = eval
 	}
 
 	// Calculate improving
 	improving := true // Default to true (conservative)
 	if ply >= 2 && !inCheck {
 		if SearchState.evalStack[ply-2] != -MaxScore {
-			improving = staticScore > SearchState.evalStack[ply-2]
+			improving = eval > SearchState.evalStack[ply-2]
 		}
 		// If ply-2 was in check, keep improving = true (conservative)
 	}
@@ -300,7 +400,8 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 		}
 		if staticScore-rfpMargin >= beta {
 			SearchState.cutStats.StaticNullCutoffs++
-			SearchState.tt.storeEntry(posHash, depth, ply, ttMove, staticScore-rfpMargin, BetaFlag)
+			SearchState.tracer.prune(PruneRFP)
+			SearchState.tt.storeEntry(posHash, depth, ply, ttMove, staticScore-rfpMargin, rawEval, BetaFlag)
 			return staticScore - rfpMargin
 		}
 	}
@@ -319,8 +420,9 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 		unApplyfunc()
 
 		if score >= beta && score < Checkmate {
-			SearchState.tt.storeEntry(posHash, depth, ply, 0, score, BetaFlag)
+			SearchState.tt.storeEntry(posHash, depth, ply, 0, score, rawEval, BetaFlag)
 			SearchState.cutStats.NullMoveCutoffs++
+			SearchState.tracer.prune(PruneNullMove)
 			return score
 		}
 
@@ -337,6 +439,7 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 			score := quiescence(b, alpha, beta, &childPVLine, 30, ply, rootIndex)
 			if score < alpha {
 				SearchState.cutStats.RazoringCutoffs++
+				SearchState.tracer.prune(PruneRazoring)
 				return score
 			}
 		}
@@ -395,8 +498,9 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 					score := -alphabeta(b, -probCutBeta, -probCutBeta+1, depth-4, ply+1, &childPVLine, prevMove, didNull, isExtended, excludedMove, rootIndex)
 					if score >= probCutBeta {
 						unapplyFunc()
-						SearchState.tt.storeEntry(posHash, depth, ply, move, score, BetaFlag)
+						SearchState.tt.storeEntry(posHash, depth, ply, move, score, rawEval, BetaFlag)
 						SearchState.cutStats.ProbCutCutoffs++
+						SearchState.tracer.prune(PruneProbCut)
 						return score
 					}
 				}
@@ -426,25 +530,19 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 		}
 	}
 
-	// Checkmate/stalemate check
-	if len(allMoves) == 0 {
-		if inCheck {
-			return -MaxScore + int32(ply) // Checkmate
-		}
-		return DrawScore // Stalemate
-	}
-
 	var score int32 = -MaxScore
 	var bestScore int32 = -MaxScore
-	var moveList = scoreMovesList(b, allMoves, depth, ply, bestMove, prevMove)
 	var ttFlag int8 = AlphaFlag
 	legalMoves := 0
+	movesPicked := 0
 
 	quietMovesTried := make([]gm.Move, 0, 16)
+	capturesTried := make([]gm.Move, 0, 8)
 
-	for index := uint8(0); index < uint8(len(moveList.moves)); index++ {
-		orderNextMove(index, &moveList)
-		move := moveList.moves[index].move
+	picker := newMovePicker(b, ply, ttMove, prevMove)
+	for move := picker.next(); move != 0; move = picker.next() {
+		index := movesPicked
+		movesPicked++
 
 		if move == excludedMove {
 			continue
@@ -474,6 +572,7 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 			}
 			if lmpMargin > 0 && legalMoves > lmpMargin {
 				SearchState.cutStats.LateMovePrunes++
+				SearchState.tracer.skip(SkipLateMove)
 				continue
 			}
 		}
@@ -491,12 +590,29 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 			}
 			if staticScore+futilityMargin <= alpha {
 				SearchState.cutStats.FutilityPrunes++
+				SearchState.tracer.skip(SkipFutility)
+				continue
+			}
+		}
+
+		/*
+			====== SEE PRUNING ======
+			Near the horizon, skip captures that lose more material than a
+			depth-scaled margin. Captures with a good history get more slack.
+		*/
+		if depth <= 6 && !isPVNode && !isRoot && !inCheck && isCapture && !moveGivesCheck && legalMoves > 0 {
+			seeMargin := SeeCaptureMargin*int(depth) + CaptHistScore(move)/CaptHistSeeDiv
+			if see(b, move, false) < -seeMargin {
+				SearchState.cutStats.SeeCapturePrunes++
+				SearchState.tracer.skip(SkipSEE)
 				continue
 			}
 		}
 
 		if isQuiet {
 			quietMovesTried = append(quietMovesTried, move)
+		} else if isCapture {
+			capturesTried = append(capturesTried, move)
 		}
 
 		var unapplyFunc = applyMoveWithState(b, move)
@@ -511,6 +627,11 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 		nextExtended := isExtended || extendMove
 
 		legalMoves++
+		SearchState.tracer.searched()
+		// GUIs show the root move being searched once a search takes a while
+		if isRoot && SearchState.printInfo && time.Since(SearchState.timeHandler.startTime) > time.Second {
+			fmt.Println("info depth", depth, "currmove", move, "currmovenumber", legalMoves)
+		}
 		if legalMoves == 1 {
 			// First move: search with full window, no reduction
 			nextDepth := calculateSearchDepth(depth-1, 0, extendMove)
@@ -521,7 +642,7 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 			var reduct int8 = 0
 			if depth >= LMRDepthLimit && legalMoves >= LMRMoveLimit && !moveGivesCheck && !tactical {
 				reduct = computeLMRReduction(
-					depth, legalMoves, int(index), isPVNode, tactical,
+					depth, legalMoves, index, isPVNode, tactical,
 					moveHistoryScore, improving,
 					IsKiller(move, ply, &SearchState.killer), extendMove,
 				)
@@ -533,12 +654,14 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 
 			// Stage 2: If we had a reduction and score beats alpha, re-search at full depth with null window
 			if score > alpha && reduct > 0 {
+				SearchState.tracer.research()
 				nextDepth = calculateSearchDepth(depth-1, 0, extendMove)
 				score = -alphabeta(b, -(alpha + 1), -alpha, nextDepth, ply+1, &childPVLine, move, false, nextExtended, 0, rootIndex)
 			}
 
 			// Stage 3: If score is within window (alpha, beta), do full window search
 			if score > alpha && score < beta {
+				SearchState.tracer.research()
 				nextDepth = calculateSearchDepth(depth-1, 0, extendMove)
 				score = -alphabeta(b, -beta, -alpha, nextDepth, ply+1, &childPVLine, move, false, nextExtended, 0, rootIndex)
 			}
@@ -553,6 +676,7 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 
 		if score >= beta {
 			SearchState.cutStats.BetaCutoffs++
+			SearchState.tracer.cutoff()
 			ttFlag = BetaFlag
 			if isQuiet {
 				InsertKiller(move, ply, &SearchState.killer)
@@ -563,6 +687,14 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 						HistoryUpdateAllBad(b.Wtomove, failedMove, ply, depth)
 					}
 				}
+			} else if isCapture {
+				CaptHistUpdateGood(move, depth)
+			}
+			// Captures searched before the cutoff move didn't refute the position
+			for _, failedCapture := range capturesTried {
+				if failedCapture != move {
+					CaptHistUpdateBad(failedCapture, depth)
+				}
 			}
 			break
 		}
@@ -579,16 +711,52 @@ func alphabeta(b *gm.Board, alpha int32, beta int32, depth int8, ply int8, pvLin
 		childPVLine.Clear()
 	}
 
+	// Checkmate/stalemate check
+	if movesPicked == 0 {
+		if inCheck {
+			return -MaxScore + int32(ply) // Checkmate
+		}
+		return SearchState.drawScore(b) // Stalemate
+	}
+
 	if !SearchState.ShouldStopNoClock() {
-		SearchState.tt.storeEntry(posHash, depth, ply, bestMove, bestScore, ttFlag)
+		SearchState.tt.storeEntry(posHash, depth, ply, bestMove, bestScore, rawEval, ttFlag)
+
+		// Learn from the eval error only when the bound points the same way:
+		// a fail high at or below the eval (or a fail low at or above it)
+		// doesn't tell how far off the eval was. Captures and promotions
+		// win material the static eval can't see.
+		if !inCheck && excludedMove == 0 && (bestMove == 0 || !isTacticalMove(bestMove)) &&
+			abs32(bestScore) < Checkmate &&
+			!(ttFlag == BetaFlag && bestScore <= eval) && !(ttFlag == AlphaFlag && bestScore >= eval) {
+			SearchState.learnEvalError(b, depth, bestScore, rawEval)
+		}
 	}
 
 	return bestScore
 }
 
+// quiescence searches captures (all moves when in check), recording the node
+// when the search tracer is on.
 func quiescence(b *gm.Board, alpha int32, beta int32, pvLine *PVLine, depth int8, ply int8, rootIndex int) int32 {
+	if !SearchState.tracer.enabled {
+		return quiescenceNode(b, alpha, beta, pvLine, depth, ply, rootIndex)
+	}
+	SearchState.tracer.begin(TraceQuiescence, depth, ply, alpha, beta)
+	score := quiescenceNode(b, alpha, beta, pvLine, depth, ply, rootIndex)
+	SearchState.tracer.end(score)
+	return score
+}
+
+func quiescenceNode(b *gm.Board, alpha int32, beta int32, pvLine *PVLine, depth int8, ply int8, rootIndex int) int32 {
 	pvLine.Clear()
 	SearchState.nodesChecked++
+	if ply > SearchState.selDepth {
+		SearchState.selDepth = ply
+	}
+	if SearchState.timeHandler.NodeLimitReached(SearchState.nodesChecked) {
+		SearchState.searchShouldStop = true
+	}
 
 	if SearchState.nodesChecked&2047 == 0 {
 		if SearchState.timeHandler.TimeStatus() {
@@ -603,12 +771,19 @@ func quiescence(b *gm.Board, alpha int32, beta int32, pvLine *PVLine, depth int8
 	inCheck := b.OurKingInCheck()
 	var childPVLine = PVLine{}
 
-	var standpat int32 = Evaluation(b, false)
+	var standpat int32
+	if inCheck {
+		standpat = SearchState.staticEval(b)
+	} else {
+		standpat = SearchState.lazyStaticEval(b, alpha, beta)
+	}
+	standpat = SearchState.contemptEval(b, standpat)
 
 	// Stand-pat pruning (not when in check)
 	if !inCheck {
 		if standpat >= beta {
 			SearchState.cutStats.QStandPatCutoffs++
+			SearchState.tracer.prune(PruneStandPat)
 			return standpat
 		}
 		if standpat > alpha {
@@ -640,7 +815,8 @@ func quiescence(b *gm.Board, alpha int32, beta int32, pvLine *PVLine, depth int8
 		if !inCheck {
 			// SEE pruning first
 			seeScore := see(b, move, false)
-			if seeScore < -QuiescenceSeeMargin {
+			if seeScore < -(QuiescenceSeeMargin + CaptHistScore(move)/CaptHistSeeDiv) {
+				SearchState.tracer.skip(SkipSEE)
 				continue
 			}
 
@@ -657,6 +833,7 @@ func quiescence(b *gm.Board, alpha int32, beta int32, pvLine *PVLine, depth int8
 
 			// If even with the capture we can't beat alpha, skip
 			if standpat+moveGain+DeltaMargin < alpha {
+				SearchState.tracer.skip(SkipDelta)
 				continue
 			}
 		}
@@ -664,6 +841,7 @@ func quiescence(b *gm.Board, alpha int32, beta int32, pvLine *PVLine, depth int8
 		unapplyFunc := applyMoveWithState(b, move)
 		SearchState.ContHistPushMove(ply, move)
 		movesSearched++
+		SearchState.tracer.searched()
 
 		score := -quiescence(b, -beta, -alpha, &childPVLine, depth-1, ply+1, rootIndex)
 		unapplyFunc()
@@ -674,6 +852,7 @@ func quiescence(b *gm.Board, alpha int32, beta int32, pvLine *PVLine, depth int8
 
 		if score >= beta {
 			SearchState.cutStats.QBetaCutoffs++
+			SearchState.tracer.cutoff()
 			return score // Return score, not beta (more accurate)
 		}
 
@@ -0,0 +1,36 @@
+package engine
+
+import (
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+// searchNode runs alphabeta on b as a non-root, non-PV node, the way the
+// search reaches it one ply below the root.
+func searchNode(b *gm.Board, beta int32, depth int8) int32 {
+	initVariables(b)
+	SearchState.ResetForNewGame()
+	SearchState.SyncPositionState(b)
+	SearchState.ResetForSearch(b)
+	SearchState.setContempt(b)
+	if !SearchState.tt.isInitialized {
+		SearchState.tt.init()
+	}
+	SearchState.timeHandler.initTimemanagement(1000000, 0, b.FullmoveNumber(), 0, true)
+	SearchState.timeHandler.StartTime(b.FullmoveNumber())
+	SearchState.timeHandler.startNodeBudget(SearchState.nodesChecked)
+	var pv PVLine
+	return alphabeta(b, beta-1, beta, depth, 1, &pv, 0, false, false, 0, len(SearchState.stateStack)-1)
+}
+
+// A stalemate scores a draw however far behind the eval says the side to
+// move is: razoring and the quiescence search must not see it.
+func TestStalemateIsNotPruned(t *testing.T) {
+	b := gm.ParseFen("7k/5Q2/6K1/8/8/8/8/8 b - - 0 1")
+	for depth := int8(0); depth <= 3; depth++ {
+		if got := searchNode(&b, -200, depth); got != DrawScore {
+			t.Errorf("depth %d: stalemate scored %d, want %d", depth, got, DrawScore)
+		}
+	}
+}
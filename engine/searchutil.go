@@ -209,6 +209,89 @@ func ContHistAge() {
 	}
 }
 
+// =============================================================================
+// CAPTURE HISTORY
+// =============================================================================
+// captHist[side][piece][to][captured] - how often capturing `captured` on `to`
+// with `piece` produced a beta cutoff. Piece types are 0-5 as above; en
+// passant counts as a pawn capture.
+//
+// MVV-LVA and SEE only look at material; capture history learns which
+// captures actually work in the current search.
+
+const captHistMax = 8000
+
+// captHistIndex returns the table coordinates of a capture; ok is false
+// for moves that capture nothing.
+func captHistIndex(mv gm.Move) (side, piece, to, captured int, ok bool) {
+	capturedType := mv.CapturedPiece().Type()
+	if mv.Flags() == gm.FlagEnPassant {
+		capturedType = gm.PieceTypePawn
+	}
+	if capturedType == gm.PieceTypeNone || capturedType == gm.PieceTypeKing {
+		return 0, 0, 0, 0, false
+	}
+	moved := mv.MovedPiece()
+	return int(moved.Color()), int(moved.Type() - 1), int(mv.To()), int(capturedType - 1), true
+}
+
+// CaptHistScore returns the capture history score of a capture (0 for quiets)
+func CaptHistScore(mv gm.Move) int {
+	side, piece, to, captured, ok := captHistIndex(mv)
+	if !ok {
+		return 0
+	}
+	return int(SearchState.captHist[side][piece][to][captured])
+}
+
+// CaptHistUpdateGood rewards a capture that caused a beta cutoff
+func CaptHistUpdateGood(mv gm.Move, depth int8) {
+	side, piece, to, captured, ok := captHistIndex(mv)
+	if !ok {
+		return
+	}
+	bonus := int(depth) * int(depth)
+	current := int(SearchState.captHist[side][piece][to][captured])
+	newVal := current + bonus - current*bonus/captHistMax
+	if newVal > captHistMax {
+		newVal = captHistMax
+	}
+	SearchState.captHist[side][piece][to][captured] = int16(newVal)
+}
+
+// CaptHistUpdateBad penalizes a capture that was searched before the cutoff move
+func CaptHistUpdateBad(mv gm.Move, depth int8) {
+	side, piece, to, captured, ok := captHistIndex(mv)
+	if !ok {
+		return
+	}
+	malus := int(depth) * int(depth)
+	current := int(SearchState.captHist[side][piece][to][captured])
+	newVal := current - malus - current*malus/captHistMax
+	if newVal < -captHistMax {
+		newVal = -captHistMax
+	}
+	SearchState.captHist[side][piece][to][captured] = int16(newVal)
+}
+
+// CaptHistAge halves all capture history values
+func CaptHistAge() {
+	for side := range SearchState.captHist {
+		for piece := range SearchState.captHist[side] {
+			for to := range SearchState.captHist[side][piece] {
+				for captured := range SearchState.captHist[side][piece][to] {
+					SearchState.captHist[side][piece][to][captured] /= 2
+				}
+			}
+		}
+	}
+}
+
+// CaptHistClear resets the capture history table
+func CaptHistClear() {
+	SearchState.captHist = [2][6][64][6]int16{}
+}
+
 // =============================================================================
 // SEARCH STATE
 // =============================================================================
@@ -224,6 +307,8 @@ type searchState struct {
 	historyMoves     [2][64][64]int
 	evalStack        [MaxDepth]int32
 	prevSearchScore  int32
+	selDepth         int8 // highest ply reached in the current iteration
+	printInfo        bool // current search prints UCI info lines
 	searchShouldStop bool
 	GlobalStop       bool
 	tt               TransTable
@@ -235,6 +320,24 @@ type searchState struct {
 	// Continuation history tables (1-ply and 2-ply)
 	contHist1Ply [2][6][64][6][64]int16
 	contHist2Ply [2][6][64][6][64]int16
+
+	// Capture history table
+	captHist [2][6][64][6]int16
+
+	// Correction history tables for the static eval
+	pawnCorrHist    [2][corrHistSize]int32
+	nonPawnCorrHist [2][2][corrHistSize]int32
+
+	// Static evals of recently evaluated positions
+	evalCache [evalCacheSize]evalCacheEntry
+
+	// Draw scores of the current search (see setContempt)
+	rootWhite bool
+	contempt  int32
+	ttKeySalt uint64
+
+	// Opt-in node recorder
+	tracer searchTracer
 }
 
 // SearchState is the package-level instance used by the engine.
@@ -272,6 +375,17 @@ func (s *searchState) ResetForNewGame() {
 	ResetForNewGame()
 }
 
+// ClearHash empties the transposition table (UCI "Clear Hash").
+func (s *searchState) ClearHash() {
+	s.tt.clearTT()
+}
+
+// ResizeHash sets the transposition table size in MB and reallocates it immediately.
+func (s *searchState) ResizeHash(sizeMB int) {
+	TTSize = sizeMB
+	s.tt.resize(sizeMB)
+}
+
 // SyncPositionState rebuilds position-tracking state for a new root position.
 func (s *searchState) SyncPositionState(board *gm.Board) {
 	SearchState.ResetStateTracking(board)
@@ -291,6 +405,19 @@ func (s *searchState) RequestStop() {
 	s.GlobalStop = true
 }
 
+// SetMoveTime makes the next search use a fixed budget of ms milliseconds.
+func (s *searchState) SetMoveTime(ms int) {
+	s.timeHandler.pendingMoveTime = ms
+}
+
+// SetNodeLimits limits the next search to hard nodes, and stops it from
+// starting a new iteration once soft nodes are spent. 0 means no limit.
+// A node-limited search ignores the clock and is fully reproducible.
+func (s *searchState) SetNodeLimits(soft, hard int) {
+	s.timeHandler.pendingSoftNodes = soft
+	s.timeHandler.pendingHardNodes = hard
+}
+
 // ClearStop clears any external stop request.
 func (s *searchState) ClearStop() {
 	s.GlobalStop = false
@@ -313,10 +440,12 @@ func (s *searchState) UpdateBetweenSearches() {
 }
 
 func UpdateBetweenSearches() {
-	HistoryAge()        // Age history
-	ContHistAge()       // Age continuation history
-	ResetNodesChecked() // Reset nodes checked
-	ResetCutStats()     // Reset cut statistics
+	HistoryAge()         // Age history
+	CaptHistAge()        // Age capture history
+	ContHistAge()        // Age continuation history
+	ResetNodesChecked()  // Reset nodes checked
+	ResetCutStats()      // Reset cut statistics
+	ResetPawnHashStats() // Reset pawn hash hit counters
 	SearchState.tt.NewSearch()
 }
 
@@ -324,9 +453,13 @@ func ResetForNewGame() {
 	SearchState.tt.clearTT()
 	SearchState.tt.NewSearch()
 	ClearPawnHash()
+	ClearMaterialHash()
+	EvalCacheClear()
 	ClearKillers(&SearchState.killer)
 	HistoryClear()
 	ContHistClear()
+	CaptHistClear()
+	CorrHistClear()
 	SearchState.stateStack = SearchState.stateStack[:0]
 	var nilMove gm.Move
 	for i := 0; i < 64; i++ {
@@ -435,6 +568,206 @@ func HistoryClear() {
 	}
 }
 
+// =============================================================================
+// CORRECTION HISTORY
+// =============================================================================
+// Correction history learns how far the static eval tends to be off for a
+// given pawn structure (and, separately, for each side's piece placement)
+// and nudges later evals of matching positions by that amount:
+//
+// pawnCorrHist[side][pawnKey % size]              - keyed by the pawn structure
+// nonPawnCorrHist[side][color][nonPawnKey % size] - keyed by color's other pieces
+//
+// Entries are kept in 1/corrHistGrain centipawns so small errors accumulate.
+
+const corrHistSize = 16384
+const corrHistGrain = 256
+const corrHistWeightScale = 256
+const corrHistMax = 64 * corrHistGrain
+
+// CorrHistApply returns eval adjusted by the correction history for the
+// position; eval must be from the side to move's point of view.
+func CorrHistApply(b *gm.Board, eval int32) int32 {
+	side := 0
+	if !b.Wtomove {
+		side = 1
+	}
+	pawn := SearchState.pawnCorrHist[side][b.PawnKey()%corrHistSize]
+	white := SearchState.nonPawnCorrHist[side][0][b.NonPawnKey(gm.White)%corrHistSize]
+	black := SearchState.nonPawnCorrHist[side][1][b.NonPawnKey(gm.Black)%corrHistSize]
+
+	// Weighted average of the three tables. The pawn table counts double:
+	// pawn structure changes slowly, so its entries see far more updates
+	// than the piece placement ones
+	corrected := eval + (2*pawn+white+black)/(4*corrHistGrain)
+	if corrected >= Checkmate {
+		corrected = Checkmate - 1
+	} else if corrected <= -Checkmate {
+		corrected = -Checkmate + 1
+	}
+	return corrected
+}
+
+// CorrHistUpdate moves the entries for the position towards the observed
+// error (searchScore - rawEval), weighting deeper searches more heavily.
+// rawEval is the eval before CorrHistApply: measured against the corrected
+// eval, the entries would settle at half the error.
+func CorrHistUpdate(b *gm.Board, depth int8, searchScore int32, rawEval int32) {
+	side := 0
+	if !b.Wtomove {
+		side = 1
+	}
+	diff := (searchScore - rawEval) * corrHistGrain
+	weight := int32(Min(int(depth)+1, 16))
+
+	update := func(entry *int32) {
+		val := (*entry*(corrHistWeightScale-weight) + diff*weight) / corrHistWeightScale
+		if val > corrHistMax {
+			val = corrHistMax
+		}
+		if val < -corrHistMax {
+			val = -corrHistMax
+		}
+		*entry = val
+	}
+	update(&SearchState.pawnCorrHist[side][b.PawnKey()%corrHistSize])
+	update(&SearchState.nonPawnCorrHist[side][0][b.NonPawnKey(gm.White)%corrHistSize])
+	update(&SearchState.nonPawnCorrHist[side][1][b.NonPawnKey(gm.Black)%corrHistSize])
+}
+
+// nodeEval is the eval alphabeta works with: rawEval with contempt and,
+// outside check, the correction history applied.
+func (s *searchState) nodeEval(b *gm.Board, rawEval int32, inCheck bool) int32 {
+	eval := s.contemptEval(b, rawEval)
+	if !inCheck {
+		eval = CorrHistApply(b, eval)
+	}
+	return eval
+}
+
+// learnEvalError feeds the result of a search of b back into the correction
+// history. The error is taken against the raw eval, since nodeEval already
+// holds the current correction, and with contempt taken out of the score.
+func (s *searchState) learnEvalError(b *gm.Board, depth int8, bestScore, rawEval int32) {
+	contemptShift := s.contemptEval(b, rawEval) - rawEval
+	CorrHistUpdate(b, depth, bestScore-contemptShift, rawEval)
+}
+
+// CorrHistClear resets all correction history tables
+func CorrHistClear() {
+	SearchState.pawnCorrHist = [2][corrHistSize]int32{}
+	SearchState.nonPawnCorrHist = [2][2][corrHistSize]int32{}
+}
+
+// =============================================================================
+// CONTEMPT
+// =============================================================================
+// Draws are scored from the root side's point of view: the side to move at
+// the root (the engine) values a draw at -contempt, its opponent at
+// +contempt. Every draw the search scores goes through drawScore: repetition
+// and 50-move draws, stalemate, and evals of theoretically drawn material,
+// which evaluate already pulls towards 0. Because the same position is then
+// worth different scores depending on who is at the root, TT keys carry the
+// root color while contempt is active.
+
+// contemptRootBlackKey salts TT keys of searches with black at the root.
+const contemptRootBlackKey uint64 = 0x9e3779b97f4a7c15
+
+// setContempt prepares the draw scores of a search from root position b.
+// Contempt applies in full with all pieces on the board and halves towards
+// the bare endgame; analysis mode turns it off.
+func (s *searchState) setContempt(b *gm.Board) {
+	s.rootWhite = b.Wtomove
+	s.contempt, s.ttKeySalt = 0, 0
+	if AnalyseMode || Contempt == 0 {
+		return
+	}
+	phase := min(GetPiecePhase(b), TotalPhase)
+	s.contempt = (Contempt*int32(phase) + Contempt/2*int32(TotalPhase-phase)) / TotalPhase
+	if !b.Wtomove {
+		s.ttKeySalt = contemptRootBlackKey
+	}
+}
+
+// drawScore is the value of a draw for the side to move in b.
+func (s *searchState) drawScore(b *gm.Board) int32 {
+	if b.Wtomove == s.rootWhite {
+		return DrawScore - s.contempt
+	}
+	return DrawScore + s.contempt
+}
+
+// contemptEval moves the eval of a theoretically drawn position (already
+// divided by DrawDivider) onto drawScore. Bare kings count as drawn here too,
+// although evaluate doesn't flag them. Evals are cached without it.
+func (s *searchState) contemptEval(b *gm.Board, eval int32) int32 {
+	if s.contempt == 0 {
+		return eval
+	}
+	bareKings := b.White.All|b.Black.All == b.White.Kings|b.Black.Kings
+	if !bareKings && !GetMaterialEntry(b).Draw {
+		return eval
+	}
+	return eval + s.drawScore(b)
+}
+
+// =============================================================================
+// EVALUATION CACHE
+// =============================================================================
+// Static evals keyed by the Zobrist hash. Search keeps evaluating the same
+// positions again through transpositions and re-searches, and quiescence
+// nodes never reach the TT, so a small direct-mapped table saves most of
+// those evaluations. Only full evaluations are stored, never lazy ones.
+
+const evalCacheSize = 1 << 16
+
+// EvalCacheEnabled turns the evaluation cache on and off; either way the
+// search must see the same evals.
+var EvalCacheEnabled = true
+
+type evalCacheEntry struct {
+	key  uint64
+	eval int32
+}
+
+// staticEval returns Evaluation(b), from the evaluation cache when it has it.
+func (s *searchState) staticEval(b *gm.Board) int32 {
+	if !EvalCacheEnabled {
+		return Evaluation(b)
+	}
+	key := b.Hash()
+	entry := &s.evalCache[key%evalCacheSize]
+	if entry.key == key && key != 0 {
+		return entry.eval
+	}
+	eval := Evaluation(b)
+	entry.key, entry.eval = key, eval
+	return eval
+}
+
+// lazyStaticEval is staticEval for quiescence stand-pat: when the lazy eval is
+// more than LazyEvalMargin outside [alpha, beta], the piece terms can't bring
+// it back into the window and the lazy eval is returned instead.
+func (s *searchState) lazyStaticEval(b *gm.Board, alpha, beta int32) int32 {
+	if EvalCacheEnabled {
+		key := b.Hash()
+		if entry := &s.evalCache[key%evalCacheSize]; entry.key == key && key != 0 {
+			return entry.eval
+		}
+	}
+	if lazy, ok := lazyEvaluation(b); ok && (lazy-LazyEvalMargin >= beta || lazy+LazyEvalMargin <= alpha) {
+		s.cutStats.LazyEvals++
+		return lazy
+	}
+	return s.staticEval(b)
+}
+
+// EvalCacheClear empties the evaluation cache. Call it whenever evaluation
+// parameters change.
+func EvalCacheClear() {
+	SearchState.evalCache = [evalCacheSize]evalCacheEntry{}
+}
+
 // =============================================================================
 // COMBINED HISTORY UPDATE (call on beta cutoff for quiet moves)
 // =============================================================================
@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+// symmetryPositions plays seeded random games from evalTraceFens and returns
+// up to n of the positions reached.
+func symmetryPositions(n int) []gm.Board {
+	rng := rand.New(rand.NewSource(49))
+	positions := make([]gm.Board, 0, n)
+	for len(positions) < n {
+		for _, fen := range evalTraceFens {
+			b := gm.ParseFen(fen)
+			for ply := 0; ply < 80 && len(positions) < n; ply++ {
+				moves := b.GenerateMoves()
+				if len(moves) == 0 {
+					break
+				}
+				b.Apply(moves[rng.Intn(len(moves))])
+				positions = append(positions, b)
+			}
+		}
+	}
+	return positions
+}
+
+// traceDiff names the trace terms that break the symmetry between b and its
+// transform c: a color flip negates every white-POV term, a mirror keeps it.
+func traceDiff(b, c *gm.Board, negate bool) string {
+	want := EvaluateWithTrace(b)
+	got := EvaluateWithTrace(c)
+	sign := 1
+	if negate {
+		sign = -1
+	}
+	terms := make(map[string]EvalScore, len(got.Terms))
+	for _, term := range got.Terms {
+		terms[term.Name] = term.Total
+	}
+	var diffs []string
+	for _, term := range want.Terms {
+		other, ok := terms[term.Name]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: missing", term.Name))
+			continue
+		}
+		if other.MG*sign != term.Total.MG || other.EG*sign != term.Total.EG {
+			diffs = append(diffs, fmt.Sprintf("%s: %+v vs %+v", term.Name, term.Total, other))
+		}
+	}
+	if want.Scale != got.Scale {
+		diffs = append(diffs, fmt.Sprintf("scale: %d vs %d", want.Scale, got.Scale))
+	}
+	if want.Endgame != got.Endgame {
+		diffs = append(diffs, fmt.Sprintf("endgame: %q vs %q", want.Endgame, got.Endgame))
+	}
+	return strings.Join(diffs, "; ")
+}
+
+// transformMove finds the move of c that is m with both squares mapped.
+func transformMove(c *gm.Board, m gm.Move, mapSq func(gm.Square) gm.Square) (gm.Move, bool) {
+	for _, cm := range c.GenerateMoves() {
+		if cm.From() == mapSq(m.From()) && cm.To() == mapSq(m.To()) && cm.PromotionPieceType() == m.PromotionPieceType() {
+			return cm, true
+		}
+	}
+	return 0, false
+}
+
+func checkSymmetry(t *testing.T, kind string, b, c *gm.Board, negate bool, mapSq func(gm.Square) gm.Square) {
+	t.Helper()
+	want := Evaluation(b)
+	if got := Evaluation(c); got != want {
+		t.Errorf("%s of %s: eval %d, original %d; terms: %s", kind, b.ToFen(), got, want, traceDiff(b, c, negate))
+	}
+
+	for _, m := range b.GenerateMoves() {
+		if m.CapturedPiece() == gm.NoPiece {
+			continue
+		}
+		cm, ok := transformMove(c, m, mapSq)
+		if !ok {
+			t.Errorf("%s of %s: no image of %s", kind, b.ToFen(), m)
+			continue
+		}
+		if want, got := see(b, m, false), see(c, cm, false); got != want {
+			t.Errorf("%s of %s: see(%s) = %d, original see(%s) = %d", kind, b.ToFen(), cm, got, m, want)
+		}
+	}
+}
+
+func TestEvaluationColorSymmetry(t *testing.T) {
+	initVariables(nil)
+	flipSq := func(sq gm.Square) gm.Square { return sq ^ 56 }
+	for _, b := range symmetryPositions(2000) {
+		c := b.FlipColors()
+		checkSymmetry(t, "color flip", &b, &c, true, flipSq)
+		if t.Failed() {
+			return
+		}
+	}
+}
+
+// symmetrizeSquareTables averages every square-indexed table with its mirror
+// image and returns a function restoring the tuned values. The tuned tables
+// are deliberately lopsided, so the mirror test only holds the rest of the
+// evaluation to left-right symmetry. The pawn hash is cleared on both ends
+// because its entries include the pawn PSQT.
+func symmetrizeSquareTables() (restore func()) {
+	psqtMG, psqtEG := PSQT_MG, PSQT_EG
+	passedMG, passedEG := PassedPawnPSQT_MG, PassedPawnPSQT_EG
+	average := func(table *[64]int) {
+		for sq := 0; sq < 64; sq++ {
+			if sq&7 < 4 {
+				avg := (table[sq] + table[sq^7]) / 2
+				table[sq], table[sq^7] = avg, avg
+			}
+		}
+	}
+	for pt := range PSQT_MG {
+		average(&PSQT_MG[pt])
+		average(&PSQT_EG[pt])
+	}
+	average(&PassedPawnPSQT_MG)
+	average(&PassedPawnPSQT_EG)
+	installPSQTables()
+	ClearPawnHash()
+	return func() {
+		PSQT_MG, PSQT_EG = psqtMG, psqtEG
+		PassedPawnPSQT_MG, PassedPawnPSQT_EG = passedMG, passedEG
+		installPSQTables()
+		ClearPawnHash()
+	}
+}
+
+func TestEvaluationMirrorSymmetry(t *testing.T) {
+	initVariables(nil)
+	defer symmetrizeSquareTables()()
+	mirrorSq := func(sq gm.Square) gm.Square { return sq ^ 7 }
+	for _, b := range symmetryPositions(2000) {
+		if b.CastlingRights() != 0 {
+			continue
+		}
+		c := b.Mirror()
+		checkSymmetry(t, "mirror", &b, &c, false, mirrorSq)
+		if t.Failed() {
+			return
+		}
+	}
+}
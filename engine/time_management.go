@@ -31,6 +31,16 @@ type TimeHandler struct {
 	usingCustomDepth     bool
 	baseAllocationMillis int64
 	movesToGo            int
+	pendingMoveTime      int  // fixed budget for the next search (UCI "go movetime"), consumed by StartTime
+	fixedMoveTime        bool // current search uses a fixed per-move budget
+
+	// Node budget (UCI "go nodes", bench, CI). Pending limits are consumed by
+	// startNodeBudget; the stops are absolute node counts, 0 when unlimited.
+	pendingSoftNodes int
+	pendingHardNodes int
+	softNodeStop     int
+	hardNodeStop     int
+	nodeLimited      bool // current search ignores the clock
 
 	// For dynamic adjustments
 	lastScore         int16
@@ -57,6 +67,16 @@ func (th *TimeHandler) StartTime(fullmoveNumber int) {
 	th.stopSearch = false
 	th.startTime = time.Now()
 
+	// Fixed move time: soft and hard limits coincide, no stability heuristics
+	th.fixedMoveTime = th.pendingMoveTime > 0
+	if th.fixedMoveTime {
+		th.baseAllocationMillis = int64(th.pendingMoveTime)
+		th.softTimeLimit = th.startTime.Add(time.Duration(th.pendingMoveTime) * time.Millisecond)
+		th.hardTimeLimit = th.softTimeLimit
+		th.pendingMoveTime = 0
+		return
+	}
+
 	// Estimate moves remaining based on game phase
 	movesRemaining := th.estimateMovesRemaining(fullmoveNumber)
 	if th.movesToGo > 0 {
@@ -93,6 +113,32 @@ func (th *TimeHandler) StartTime(fullmoveNumber int) {
 	th.hardTimeLimit = th.startTime.Add(time.Duration(hardMillis) * time.Millisecond)
 }
 
+// startNodeBudget applies the pending node limits to a search starting with
+// nodesSoFar nodes on the counter. A node-limited search never looks at the
+// clock, so its result depends only on the position, TT size and limits.
+func (th *TimeHandler) startNodeBudget(nodesSoFar int) {
+	th.softNodeStop, th.hardNodeStop = 0, 0
+	if th.pendingSoftNodes > 0 {
+		th.softNodeStop = nodesSoFar + th.pendingSoftNodes
+	}
+	if th.pendingHardNodes > 0 {
+		th.hardNodeStop = nodesSoFar + th.pendingHardNodes
+	}
+	th.nodeLimited = th.softNodeStop > 0 || th.hardNodeStop > 0
+	th.pendingSoftNodes, th.pendingHardNodes = 0, 0
+}
+
+// NodeLimitReached reports whether the hard node limit stops the search
+func (th *TimeHandler) NodeLimitReached(nodes int) bool {
+	return th.hardNodeStop > 0 && nodes >= th.hardNodeStop
+}
+
+// SoftNodesExceeded reports whether the soft node limit forbids starting
+// another iteration
+func (th *TimeHandler) SoftNodesExceeded(nodes int) bool {
+	return th.softNodeStop > 0 && nodes >= th.softNodeStop
+}
+
 func (th *TimeHandler) estimateMovesRemaining(fullmoveNumber int) int {
 	// Simple model: expect game to last expectedGameLength moves
 	// But always assume at least minMovesRemaining
@@ -195,7 +241,7 @@ func (th *TimeHandler) maxHardLimitMillis() int64 {
 // TimeStatus returns true if we should stop searching
 // This checks the HARD limit - we must stop
 func (th *TimeHandler) TimeStatus() bool {
-	if th.usingCustomDepth {
+	if th.usingCustomDepth || th.nodeLimited {
 		return false
 	}
 	return !th.hardTimeLimit.IsZero() && time.Now().After(th.hardTimeLimit)
@@ -204,7 +250,7 @@ func (th *TimeHandler) TimeStatus() bool {
 // SoftTimeExceeded returns true if we've passed the soft limit
 // Use this to decide whether to start a new iteration
 func (th *TimeHandler) SoftTimeExceeded() bool {
-	if th.usingCustomDepth {
+	if th.usingCustomDepth || th.nodeLimited {
 		return false
 	}
 	return !th.softTimeLimit.IsZero() && time.Now().After(th.softTimeLimit)
@@ -236,7 +282,7 @@ func (th *TimeHandler) UpdateStability(score int16, bestMove uint32) {
 // ShouldStopEarly returns true if we can stop before soft limit
 // due to very stable position
 func (th *TimeHandler) ShouldStopEarly() bool {
-	if th.usingCustomDepth {
+	if th.usingCustomDepth || th.fixedMoveTime || th.nodeLimited {
 		return false
 	}
 
@@ -263,7 +309,7 @@ func (th *TimeHandler) ShouldExtendTime() bool {
 
 // ExtendTime adds additional time when position is complex
 func (th *TimeHandler) ExtendTime() {
-	if th.usingCustomDepth {
+	if th.usingCustomDepth || th.fixedMoveTime || th.nodeLimited {
 		return
 	}
 
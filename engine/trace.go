@@ -0,0 +1,209 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// =============================================================================
+// SEARCH TRACER
+// =============================================================================
+// An opt-in recorder for alphabeta and quiescence nodes. Every finished node
+// becomes one TraceEvent; the tracer keeps the most recent ones in a ring
+// buffer so a long search can't grow it without bound. Events are emitted
+// when a node returns, so children come before their parent.
+//
+// With tracing off each hook costs a single branch.
+
+// Node kinds
+const (
+	TraceAlphaBeta  = "ab"
+	TraceQuiescence = "qs"
+)
+
+// TT outcomes of an alphabeta node
+const (
+	TTMiss = "miss"
+	TTHit  = "hit"
+	TTCut  = "cut"
+)
+
+// Techniques that end a node before (or instead of) the move loop
+const (
+	PruneTT       = "tt"
+	PruneRFP      = "rfp"
+	PruneNullMove = "nmp"
+	PruneRazoring = "razor"
+	PruneProbCut  = "probcut"
+	PruneStandPat = "standpat"
+)
+
+// Techniques that skip single moves inside the move loop
+const (
+	SkipFutility = "futility"
+	SkipLateMove = "lmp"
+	SkipSEE      = "see"
+	SkipDelta    = "delta"
+)
+
+// TraceEvent is the record of one searched node.
+type TraceEvent struct {
+	Kind       string `json:"kind"`
+	Depth      int8   `json:"depth"`
+	Ply        int8   `json:"ply"`
+	Alpha      int32  `json:"alpha"`
+	Beta       int32  `json:"beta"`
+	PV         bool   `json:"pv"`
+	TT         string `json:"tt,omitempty"`
+	Prune      string `json:"prune,omitempty"` // technique that ended the node early
+	Futility   int    `json:"futility,omitempty"`
+	LateMove   int    `json:"lmp,omitempty"`
+	SEE        int    `json:"see,omitempty"`
+	Delta      int    `json:"delta,omitempty"`
+	Researches int    `json:"researches,omitempty"`
+	Moves      int    `json:"moves"`     // moves actually searched
+	CutIndex   int    `json:"cut_index"` // index of the fail-high move among them, -1 if none
+	Score      int32  `json:"score"`
+}
+
+const traceStackSize = 512
+
+type searchTracer struct {
+	enabled bool
+
+	// Nodes in progress. Singular verification, IID and razoring search the
+	// same ply again, so this is a stack rather than a per-ply array.
+	stack [traceStackSize]TraceEvent
+	top   int
+
+	ring []TraceEvent
+	next int
+	full bool
+}
+
+// EnableTrace starts recording search nodes, keeping the last capacity events.
+func EnableTrace(capacity int) {
+	t := &SearchState.tracer
+	if capacity < 1 {
+		capacity = 1
+	}
+	t.ring = make([]TraceEvent, capacity)
+	t.next, t.full, t.top = 0, false, 0
+	t.enabled = true
+}
+
+// DisableTrace stops recording; events recorded so far stay available.
+func DisableTrace() {
+	SearchState.tracer.enabled = false
+}
+
+// TraceEvents returns the recorded events, oldest first.
+func TraceEvents() []TraceEvent {
+	t := &SearchState.tracer
+	if !t.full {
+		return append([]TraceEvent(nil), t.ring[:t.next]...)
+	}
+	events := make([]TraceEvent, 0, len(t.ring))
+	events = append(events, t.ring[t.next:]...)
+	return append(events, t.ring[:t.next]...)
+}
+
+// WriteTrace writes the recorded events as JSON lines, oldest first.
+func WriteTrace(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, ev := range TraceEvents() {
+		if err := enc.Encode(ev); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func (t *searchTracer) begin(kind string, depth, ply int8, alpha, beta int32) {
+	if !t.enabled {
+		return
+	}
+	if t.top < traceStackSize {
+		t.stack[t.top] = TraceEvent{
+			Kind: kind, Depth: depth, Ply: ply, Alpha: alpha, Beta: beta,
+			PV: beta-alpha > 1, CutIndex: -1,
+		}
+	}
+	t.top++
+}
+
+func (t *searchTracer) end(score int32) {
+	if !t.enabled {
+		return
+	}
+	t.top--
+	if t.top >= traceStackSize || len(t.ring) == 0 {
+		return
+	}
+	ev := &t.stack[t.top]
+	ev.Score = score
+	t.ring[t.next] = *ev
+	t.next++
+	if t.next == len(t.ring) {
+		t.next = 0
+		t.full = true
+	}
+}
+
+// node returns the node being searched, or nil when not recording it.
+func (t *searchTracer) node() *TraceEvent {
+	if !t.enabled || t.top == 0 || t.top > traceStackSize {
+		return nil
+	}
+	return &t.stack[t.top-1]
+}
+
+func (t *searchTracer) tt(outcome string) {
+	if ev := t.node(); ev != nil {
+		ev.TT = outcome
+	}
+}
+
+func (t *searchTracer) prune(technique string) {
+	if ev := t.node(); ev != nil {
+		ev.Prune = technique
+	}
+}
+
+// skip counts a move pruned inside the move loop.
+func (t *searchTracer) skip(technique string) {
+	ev := t.node()
+	if ev == nil {
+		return
+	}
+	switch technique {
+	case SkipFutility:
+		ev.Futility++
+	case SkipLateMove:
+		ev.LateMove++
+	case SkipSEE:
+		ev.SEE++
+	case SkipDelta:
+		ev.Delta++
+	}
+}
+
+func (t *searchTracer) searched() {
+	if ev := t.node(); ev != nil {
+		ev.Moves++
+	}
+}
+
+func (t *searchTracer) research() {
+	if ev := t.node(); ev != nil {
+		ev.Researches++
+	}
+}
+
+func (t *searchTracer) cutoff() {
+	if ev := t.node(); ev != nil {
+		ev.CutIndex = ev.Moves - 1
+	}
+}
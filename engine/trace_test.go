@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+func traceSearch(t *testing.T, capacity int) []TraceEvent {
+	t.Helper()
+	EnableTrace(capacity)
+	defer DisableTrace()
+	b := gm.ParseFen("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+	SearchState.ResetForNewGame()
+	SearchState.SyncPositionState(&b)
+	StartSearch(&b, 4, 1000000, 0, 0, true, false, false, false)
+	return TraceEvents()
+}
+
+func TestTraceRecordsEveryNode(t *testing.T) {
+	events := traceSearch(t, 1<<20)
+	if len(events) != GetNodeCount() {
+		t.Fatalf("recorded %d events for %d nodes", len(events), GetNodeCount())
+	}
+	if SearchState.tracer.top != 0 {
+		t.Fatalf("node stack not balanced: %d open nodes", SearchState.tracer.top)
+	}
+	root := events[len(events)-1]
+	if root.Kind != TraceAlphaBeta || root.Ply != 0 || root.Depth != 4 {
+		t.Errorf("last event should be the depth 4 root, got %+v", root)
+	}
+	for _, ev := range events {
+		if ev.CutIndex >= ev.Moves {
+			t.Fatalf("cut index %d with only %d moves searched: %+v", ev.CutIndex, ev.Moves, ev)
+		}
+		if ev.Kind == TraceAlphaBeta && ev.Ply > 0 && ev.Depth > 0 && ev.TT == "" && ev.Prune == "" && ev.Moves > 0 {
+			t.Fatalf("searched alphabeta node without TT outcome: %+v", ev)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTrace(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var first TraceEvent
+	if err := json.NewDecoder(&buf).Decode(&first); err != nil {
+		t.Fatal(err)
+	}
+	if first != events[0] {
+		t.Errorf("JSON round trip changed the event: %+v vs %+v", first, events[0])
+	}
+}
+
+func TestTraceRingKeepsNewestEvents(t *testing.T) {
+	all := traceSearch(t, 1<<20)
+	const capacity = 100
+	tail := traceSearch(t, capacity)
+	if len(tail) != capacity {
+		t.Fatalf("ring returned %d events, want %d", len(tail), capacity)
+	}
+	// The search is deterministic, so the ring holds the last events of the full run
+	for i, ev := range tail {
+		if want := all[len(all)-capacity+i]; ev != want {
+			t.Fatalf("event %d: got %+v, want %+v", i, ev, want)
+		}
+	}
+}
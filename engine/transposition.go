@@ -1,6 +1,11 @@
 package engine
 
 import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
 	gm "chess-engine/goosemg"
 )
 
@@ -13,6 +18,9 @@ const (
 	// Unusable score
 	UnusableScore int32 = -32500
 
+	// Eval stored for nodes whose static evaluation wasn't computed
+	NoEval int32 = -32500
+
 	// Number of entries per bucket
 	BucketSize = 4
 )
@@ -20,21 +28,78 @@ const (
 // TTSize is the configured transposition table size in MB.
 var TTSize = 256
 
-// TTEntry represents a single transposition table entry
-// Optimized for size: 16 bytes with this layout
+// TTEntry is the decoded view of a transposition table slot. Probes return
+// a copy, so the caller never reads a slot another searcher is writing.
 type TTEntry struct {
-	Hash       uint32  // Upper 32 bits of hash (lower bits are implicit from index)
+	Hash       uint32  // Upper 32 bits of hash (0 = empty)
 	Move       gm.Move // Move that caused this position
 	Score      int32   // Score from search
+	Eval       int32   // Static evaluation of the position, or NoEval
 	Depth      int8    // Search depth
 	Flag       int8    // Alpha/Beta/Exact flag
 	Generation uint8   // Which search this entry is from
 }
 
+// Packed layout of ttSlot.data (from LSB to MSB)
+const (
+	ttMoveMask   = 1<<26 - 1 // 26 bits: every field of gm.Move
+	ttScoreShift = 26        // 16 bits, signed
+	ttDepthShift = 42        // 8 bits, signed
+	ttFlagShift  = 50        // 2 bits
+	ttGenShift   = 52        // 8 bits
+)
+
+// Packed layout of the verified half of ttSlot.key (before the XOR with data)
+const (
+	ttEvalShift  = 16        // 16 bits, signed
+	ttCheckShift = 32        // 32 bits: upper half of the hash
+	ttZeroMask   = 1<<16 - 1 // always zero; a torn slot almost never decodes to zero here
+)
+
+// ttSlot is one lockless entry: 16 bytes in two 64-bit words. key holds
+// (hash check | eval) ^ data, so a slot torn by two concurrent writers fails
+// verification on probe instead of returning a mix of both entries.
+type ttSlot struct {
+	key  atomic.Uint64
+	data atomic.Uint64
+}
+
+func packTTData(move gm.Move, score int32, depth int8, flag int8, generation uint8) uint64 {
+	return uint64(move)&ttMoveMask |
+		uint64(uint16(int16(score)))<<ttScoreShift |
+		uint64(uint8(depth))<<ttDepthShift |
+		uint64(flag&0x3)<<ttFlagShift |
+		uint64(generation)<<ttGenShift
+}
+
+// load returns the slot decoded; Hash is 0 for empty and torn slots.
+func (slot *ttSlot) load() TTEntry {
+	data := slot.data.Load()
+	check := slot.key.Load() ^ data
+	if check&ttZeroMask != 0 {
+		return TTEntry{}
+	}
+	return TTEntry{
+		Hash:       uint32(check >> ttCheckShift),
+		Move:       gm.Move(data & ttMoveMask),
+		Score:      int32(int16(data >> ttScoreShift)),
+		Eval:       int32(int16(check >> ttEvalShift)),
+		Depth:      int8(data >> ttDepthShift),
+		Flag:       int8((data >> ttFlagShift) & 0x3),
+		Generation: uint8(data >> ttGenShift),
+	}
+}
+
+func (slot *ttSlot) store(hash uint64, eval int32, data uint64) {
+	check := hash&^(1<<ttCheckShift-1) | uint64(uint16(int16(eval)))<<ttEvalShift
+	slot.data.Store(data)
+	slot.key.Store(check ^ data)
+}
+
 // TTBucket holds multiple entries for the same hash index
 // This improves hit rates and reduces destructive collisions
 type TTBucket struct {
-	Entries [BucketSize]TTEntry
+	Entries [BucketSize]ttSlot
 }
 
 // TransTable is the main transposition table structure
@@ -45,10 +110,9 @@ type TransTable struct {
 	generation    uint8  // Current search generation (incremented each new search)
 }
 
-// clearTT resets the transposition table
+// clearTT empties the transposition table while keeping its allocation
 func (TT *TransTable) clearTT() {
-	TT.buckets = nil
-	TT.isInitialized = false
+	zeroBuckets(TT.buckets)
 	TT.generation = 0
 }
 
@@ -64,54 +128,104 @@ func (TT *TransTable) NewSearch() {
 
 // init initializes the transposition table with the configured size
 func (TT *TransTable) init() {
-	// Calculate number of buckets based on memory size
-	// Each bucket is BucketSize * 16 bytes = 32 bytes for BucketSize=2
-	bucketBytes := uint64(BucketSize * 16)
-	TT.size = (uint64(TTSize) * 1024 * 1024) / bucketBytes
-	TT.buckets = make([]TTBucket, TT.size)
+	TT.resize(TTSize)
+}
+
+// resize reallocates the table to sizeMB megabytes. Any bucket count is
+// allowed; index() maps hashes onto it with a multiply-shift instead of a
+// power-of-two mask, so every megabyte requested is used.
+func (TT *TransTable) resize(sizeMB int) {
+	bucketBytes := uint64(unsafe.Sizeof(TTBucket{}))
+	size := (uint64(sizeMB) * 1024 * 1024) / bucketBytes
+	if size == 0 {
+		size = 1
+	}
+	if TT.isInitialized && size == TT.size {
+		TT.clearTT()
+		return
+	}
+
+	// Drop the old table first so a large resize doesn't hold both in memory
+	if TT.buckets != nil {
+		TT.buckets = nil
+		runtime.GC()
+	}
+	TT.buckets = make([]TTBucket, size)
+	TT.size = size
+	// Touch every page up front (in parallel) so the first search doesn't pay for page faults
+	zeroBuckets(TT.buckets)
 	TT.generation = 0
 	TT.isInitialized = true
 }
 
-// Empty entry returned when TT is not initialized or no match found
-var emptyEntry TTEntry
+// index maps a hash onto a bucket. The low 32 bits select the bucket via
+// multiply-shift; the high 32 bits are verified against the slot key on probe.
+func (TT *TransTable) index(hash uint64) uint64 {
+	return (uint64(uint32(hash)) * TT.size) >> 32
+}
+
+// zeroBucketsChunk is the number of buckets cleared per goroutine (4 MB).
+const zeroBucketsChunk = 1 << 16
+
+// zeroBuckets clears the table, spreading large tables across all CPUs.
+func zeroBuckets(buckets []TTBucket) {
+	n := len(buckets)
+	if n <= zeroBucketsChunk {
+		clear(buckets)
+		return
+	}
+	workers := runtime.NumCPU()
+	per := (n + workers - 1) / workers
+	if per < zeroBucketsChunk {
+		per = zeroBucketsChunk
+	}
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += per {
+		end := Min(start+per, n)
+		wg.Add(1)
+		go func(part []TTBucket) {
+			defer wg.Done()
+			clear(part)
+		}(buckets[start:end])
+	}
+	wg.Wait()
+}
 
 // ProbeEntry looks up an entry and returns both the entry and whether it matched
 // This is the preferred method when you need to know if the entry is valid
-func (TT *TransTable) ProbeEntry(hash uint64) (entry *TTEntry, found bool) {
+func (TT *TransTable) ProbeEntry(hash uint64) (entry TTEntry, found bool) {
 	if !TT.isInitialized {
-		return &emptyEntry, false
+		return TTEntry{}, false
 	}
 
-	bucketIdx := hash % TT.size
+	bucketIdx := TT.index(hash)
 	bucket := &TT.buckets[bucketIdx]
 	hashHigh := uint32(hash >> 32)
 
 	// Check all entries in the bucket for a match
 	for i := 0; i < BucketSize; i++ {
-		if bucket.Entries[i].Hash == hashHigh {
-			return &bucket.Entries[i], true
+		if e := bucket.Entries[i].load(); e.Hash == hashHigh {
+			return e, true
 		}
 	}
 
-	return &emptyEntry, false
+	return TTEntry{}, false
 }
 
 // useEntry determines if a TT entry can be used to cutoff search
 // Returns (usable, score) where usable indicates if we can use this entry
 // Note: This assumes the entry was obtained via ProbeEntry and matched
-func (TT *TransTable) useEntry(ttEntry *TTEntry, hash uint64, depth int8, alpha int32, beta int32, ply int8, excludedMove gm.Move) (usable bool, score int32) {
+func (TT *TransTable) useEntry(ttEntry TTEntry, hash uint64, depth int8, alpha int32, beta int32, ply int8, excludedMove gm.Move) (usable bool, score int32) {
 	score = UnusableScore
 	usable = false
 
 	// Empty entry check
-	if ttEntry == nil || ttEntry.Hash == 0 {
+	if ttEntry.Hash == 0 {
 		return false, score
 	}
 
 	// Verify hash matches (upper 32 bits) - defensive check
-	hashHigh := uint32(hash >> 32)
-	if ttEntry.Hash != hashHigh {
+	if ttEntry.Hash != uint32(hash>>32) {
 		return false, score
 	}
 
@@ -152,14 +266,15 @@ func (TT *TransTable) useEntry(ttEntry *TTEntry, hash uint64, depth int8, alpha
 	return usable, score
 }
 
-// storeEntry stores a position in the transposition table
+// storeEntry stores a position in the transposition table together with its
+// static evaluation (NoEval if it wasn't computed)
 // Uses a scoring system to determine which entry to replace
-func (TT *TransTable) storeEntry(hash uint64, depth int8, ply int8, move gm.Move, score int32, flag int8) {
+func (TT *TransTable) storeEntry(hash uint64, depth int8, ply int8, move gm.Move, score int32, eval int32, flag int8) {
 	if !TT.isInitialized {
 		return
 	}
 
-	bucketIdx := hash % TT.size
+	bucketIdx := TT.index(hash)
 	bucket := &TT.buckets[bucketIdx]
 	hashHigh := uint32(hash >> 32)
 
@@ -171,22 +286,26 @@ func (TT *TransTable) storeEntry(hash uint64, depth int8, ply int8, move gm.Move
 		score -= int32(ply)
 	}
 
+	// Snapshot the bucket once; other searchers may rewrite it concurrently
+	var entries [BucketSize]TTEntry
+	for i := 0; i < BucketSize; i++ {
+		entries[i] = bucket.Entries[i].load()
+	}
+
 	// First pass: check if position already exists in bucket
 	for i := 0; i < BucketSize; i++ {
-		if bucket.Entries[i].Hash == hashHigh {
+		if entries[i].Hash == hashHigh {
 			// Position exists - update it if new info is better or same depth
 			// Always update if: same/deeper depth, or entry is from old search
-			existing := &bucket.Entries[i]
+			existing := &entries[i]
+			if eval == NoEval {
+				eval = existing.Eval
+			}
 			if depth >= existing.Depth || existing.Generation != TT.generation {
-				existing.Hash = hashHigh
-				existing.Move = move
-				existing.Score = score
-				existing.Depth = depth
-				existing.Flag = flag
-				existing.Generation = TT.generation
+				bucket.Entries[i].store(hash, eval, packTTData(move, score, depth, flag, TT.generation))
 			} else if move != 0 && existing.Move == 0 {
 				// At minimum, store the move if we didn't have one
-				existing.Move = move
+				bucket.Entries[i].store(hash, eval, packTTData(move, existing.Score, existing.Depth, existing.Flag, existing.Generation))
 			}
 			return
 		}
@@ -194,10 +313,10 @@ func (TT *TransTable) storeEntry(hash uint64, depth int8, ply int8, move gm.Move
 
 	// Second pass: find the best entry to replace
 	replaceIdx := 0
-	worstScore := TT.scoreEntryForReplacement(&bucket.Entries[0], depth)
+	worstScore := TT.scoreEntryForReplacement(&entries[0], depth)
 
 	for i := 1; i < BucketSize; i++ {
-		entryScore := TT.scoreEntryForReplacement(&bucket.Entries[i], depth)
+		entryScore := TT.scoreEntryForReplacement(&entries[i], depth)
 		if entryScore < worstScore {
 			worstScore = entryScore
 			replaceIdx = i
@@ -205,13 +324,7 @@ func (TT *TransTable) storeEntry(hash uint64, depth int8, ply int8, move gm.Move
 	}
 
 	// Replace the selected entry
-	entry := &bucket.Entries[replaceIdx]
-	entry.Hash = hashHigh
-	entry.Move = move
-	entry.Score = score
-	entry.Depth = depth
-	entry.Flag = flag
-	entry.Generation = TT.generation
+	bucket.Entries[replaceIdx].store(hash, eval, packTTData(move, score, depth, flag, TT.generation))
 }
 
 // scoreEntryForReplacement calculates a priority score for an entry
@@ -260,7 +373,7 @@ func (TT *TransTable) scoreEntryForReplacement(entry *TTEntry, newDepth int8) in
 // helps the compiler/runtime with memory access patterns
 func (TT *TransTable) Prefetch(hash uint64) {
 	if TT.isInitialized {
-		_ = TT.buckets[hash%TT.size]
+		_ = TT.buckets[TT.index(hash)].Entries[0].key.Load()
 	}
 }
 
@@ -281,7 +394,7 @@ func (TT *TransTable) GetHashfull() int {
 	for i := uint64(0); i < sampleSize; i++ {
 		bucket := &TT.buckets[i]
 		for j := 0; j < BucketSize; j++ {
-			if bucket.Entries[j].Hash != 0 && bucket.Entries[j].Generation == TT.generation {
+			if e := bucket.Entries[j].load(); e.Hash != 0 && e.Generation == TT.generation {
 				used++
 			}
 		}
@@ -298,14 +411,14 @@ func (TT *TransTable) GetTTMove(hash uint64) gm.Move {
 		return 0
 	}
 
-	bucketIdx := hash % TT.size
+	bucketIdx := TT.index(hash)
 	bucket := &TT.buckets[bucketIdx]
 	hashHigh := uint32(hash >> 32)
 
 	// Check all entries in the bucket for a match
 	for i := 0; i < BucketSize; i++ {
-		if bucket.Entries[i].Hash == hashHigh {
-			return bucket.Entries[i].Move
+		if e := bucket.Entries[i].load(); e.Hash == hashHigh {
+			return e.Move
 		}
 	}
 
@@ -319,6 +432,10 @@ type TTStats struct {
 	EntriesPerSlot int
 	Generation     uint8
 	Hashfull       int
+
+	// Pawn hash table, which the evaluation probes on every call
+	PawnHashEntries int
+	PawnHashHitRate int // per mille of probes since the search started
 }
 
 func (TT *TransTable) Stats() TTStats {
@@ -328,5 +445,8 @@ func (TT *TransTable) Stats() TTStats {
 		EntriesPerSlot: BucketSize,
 		Generation:     TT.generation,
 		Hashfull:       TT.GetHashfull(),
+
+		PawnHashEntries: len(PawnHashTable),
+		PawnHashHitRate: PawnHashHitRate(),
 	}
 }
@@ -0,0 +1,228 @@
+package engine
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+func newTestTT(sizeMB int) *TransTable {
+	var tt TransTable
+	tt.resize(sizeMB)
+	tt.NewSearch()
+	return &tt
+}
+
+func TestTTPackRoundTrip(t *testing.T) {
+	tt := newTestTT(1)
+	move := gm.NewMove(gm.Square(12), gm.Square(28), gm.WhitePawn, gm.BlackKnight, gm.WhiteQueen, gm.FlagEnPassant)
+	cases := []struct {
+		score int32
+		eval  int32
+		depth int8
+		flag  int8
+	}{
+		{0, 0, 0, AlphaFlag},
+		{-MaxScore, NoEval, 1, BetaFlag},
+		{MaxScore, 3000, 127, ExactFlag},
+		{-1234, -77, -1, ExactFlag},
+	}
+	for i, c := range cases {
+		hash := uint64(0x9E3779B97F4A7C15) * uint64(i+1)
+		tt.storeEntry(hash, c.depth, 0, move, c.score, c.eval, c.flag)
+		e, ok := tt.ProbeEntry(hash)
+		if !ok {
+			t.Fatalf("case %d: entry not found", i)
+		}
+		if e.Move != move || e.Score != c.score || e.Eval != c.eval || e.Depth != c.depth || e.Flag != c.flag || e.Generation != tt.generation {
+			t.Errorf("case %d: got %+v, want move %v score %d eval %d depth %d flag %d", i, e, move, c.score, c.eval, c.depth, c.flag)
+		}
+		if got := tt.GetTTMove(hash); got != move {
+			t.Errorf("case %d: GetTTMove = %v, want %v", i, got, move)
+		}
+	}
+}
+
+func TestTTReplacementKeepsDeepEntries(t *testing.T) {
+	tt := newTestTT(1)
+	// Hashes sharing the low 32 bits land in the same bucket.
+	base := uint64(0x1234)
+	hashes := make([]uint64, BucketSize+1)
+	for i := range hashes {
+		hashes[i] = base | uint64(i+1)<<32
+	}
+	for i := 0; i < BucketSize; i++ {
+		tt.storeEntry(hashes[i], int8(10+i), 0, 0, 0, NoEval, AlphaFlag)
+	}
+	tt.storeEntry(hashes[BucketSize], 1, 0, 0, 0, NoEval, AlphaFlag)
+	if _, ok := tt.ProbeEntry(hashes[0]); ok {
+		t.Errorf("shallowest entry should have been replaced")
+	}
+	for i := 1; i <= BucketSize; i++ {
+		if _, ok := tt.ProbeEntry(hashes[i]); !ok {
+			t.Errorf("entry %d missing after replacement", i)
+		}
+	}
+
+	// A shallower store for an existing position of the current search keeps
+	// the deeper result.
+	tt.storeEntry(hashes[2], 3, 0, 0, 99, NoEval, ExactFlag)
+	if e, _ := tt.ProbeEntry(hashes[2]); e.Depth != 12 {
+		t.Errorf("shallow store overwrote deeper entry: depth %d", e.Depth)
+	}
+
+	// Entries from an older search are refreshed regardless of depth.
+	tt.NewSearch()
+	tt.storeEntry(hashes[2], 3, 0, 0, 99, NoEval, ExactFlag)
+	if e, _ := tt.ProbeEntry(hashes[2]); e.Depth != 3 || e.Score != 99 || e.Generation != tt.generation {
+		t.Errorf("stale entry not refreshed: %+v", e)
+	}
+}
+
+// TestTTConcurrentAccess hammers a tiny table from several goroutines. Run
+// with -race; every hit must be an entry some writer actually stored.
+func TestTTConcurrentAccess(t *testing.T) {
+	tt := newTestTT(1)
+	const writers = 8
+	const iterations = 20000
+
+	// Every writer stores data derived from the hash, so a torn entry would
+	// show up as a mismatch between the hash and its payload.
+	payload := func(hash uint64) (gm.Move, int32, int32, int8) {
+		return gm.Move(hash & ttMoveMask), int32(int16(hash >> 20)), int32(int16(hash >> 44)), int8(hash>>40) & 0x3F
+	}
+
+	// Writers and readers share one key set: six keys for each of eight
+	// buckets (the low 32 bits pick the bucket), so stores collide and
+	// replace each other while probes still hit entries other goroutines
+	// stored.
+	keyRng := rand.New(rand.NewSource(30))
+	keys := make([]uint64, 6*8)
+	for i := range keys {
+		keys[i] = keyRng.Uint64()&^0xFFFFFFFF | uint64(i%8)<<29
+	}
+
+	var wg sync.WaitGroup
+	var hits atomic.Int64
+	errs := make(chan string, writers)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < iterations; i++ {
+				hash := keys[rng.Intn(len(keys))]
+				move, score, eval, depth := payload(hash)
+				tt.storeEntry(hash, depth, 0, move, score, eval, ExactFlag)
+
+				probe := keys[rng.Intn(len(keys))]
+				if e, ok := tt.ProbeEntry(probe); ok {
+					hits.Add(1)
+					m, s, ev, d := payload(probe)
+					if e.Move != m || e.Score != s || e.Eval != ev || e.Depth != d {
+						select {
+						case errs <- "torn entry returned by ProbeEntry":
+						default:
+						}
+						return
+					}
+				}
+				_ = tt.GetTTMove(probe)
+			}
+		}(int64(w + 1))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if hits.Load() == 0 {
+		t.Errorf("no probe hit a stored entry")
+	}
+}
+
+func TestTTKeepsEvalOnMoveOnlyUpdate(t *testing.T) {
+	tt := newTestTT(1)
+	hash := uint64(0xDEADBEEF12345678)
+	tt.storeEntry(hash, 8, 0, 0, 50, 42, AlphaFlag)
+	// Shallower store without an eval: only the move is filled in
+	move := gm.NewMove(gm.Square(1), gm.Square(18), gm.WhiteKnight, gm.NoPiece, gm.NoPiece, gm.FlagNone)
+	tt.storeEntry(hash, 2, 0, move, -10, NoEval, BetaFlag)
+	e, ok := tt.ProbeEntry(hash)
+	if !ok || e.Eval != 42 || e.Move != move || e.Depth != 8 || e.Score != 50 {
+		t.Errorf("got %+v, want eval 42, move %v, depth 8, score 50", e, move)
+	}
+}
+
+func TestPawnHashResizeAndHitRate(t *testing.T) {
+	initVariables(nil)
+	defer ResizePawnHash(PawnHashSizeMB)
+	ResizePawnHash(1)
+	if got := SearchState.tt.Stats().PawnHashEntries; got != len(PawnHashTable) || got < 1000 {
+		t.Fatalf("1 MB pawn hash has %d entries", got)
+	}
+
+	ResetPawnHashStats()
+	b := gm.ParseFen(gm.Startpos)
+	first := Evaluation(&b)
+	// A knight move keeps the pawn key, so the second probe hits
+	b.MakeMove(gm.NewMove(gm.Square(6), gm.Square(21), gm.WhiteKnight, gm.NoPiece, gm.NoPiece, 0))
+	Evaluation(&b)
+	if rate := SearchState.tt.Stats().PawnHashHitRate; rate != 500 {
+		t.Errorf("hit rate %d/1000 after one miss and one hit", rate)
+	}
+	b = gm.ParseFen(gm.Startpos)
+	if again := Evaluation(&b); again != first {
+		t.Errorf("cached pawn entry changed the evaluation: %d, want %d", again, first)
+	}
+}
+
+// pawnsAndKings drops every piece but the pawns and kings from fen.
+func pawnsAndKings(fen string) string {
+	placement, rest, _ := strings.Cut(fen, " ")
+	var sb strings.Builder
+	empty := 0
+	for _, c := range placement {
+		switch {
+		case strings.ContainsRune("nbrqNBRQ", c):
+			empty++
+			continue
+		case c >= '1' && c <= '8':
+			empty += int(c - '0')
+			continue
+		}
+		if empty > 0 {
+			sb.WriteByte(byte('0' + empty))
+			empty = 0
+		}
+		sb.WriteRune(c)
+	}
+	if empty > 0 {
+		sb.WriteByte(byte('0' + empty))
+	}
+	return sb.String() + " " + rest
+}
+
+// The pawn hash is keyed on the pawns alone, so nothing in an entry may
+// depend on the other pieces.
+func TestPawnEntryReadsOnlyPawns(t *testing.T) {
+	initVariables(nil)
+	fens := []string{
+		// The knight on d5 stands in the way of the lever push d4-d5
+		"4k3/8/4p3/3n4/3P4/8/8/4K3 w - - 0 1",
+	}
+	for _, b := range symmetryPositions(500) {
+		fens = append(fens, b.ToFen())
+	}
+	for _, fen := range fens {
+		b := gm.ParseFen(fen)
+		bare := gm.ParseFen(pawnsAndKings(fen))
+		if ComputePawnEntry(&b, nil) != ComputePawnEntry(&bare, nil) {
+			t.Errorf("%s: pawn entry differs from %s", fen, bare.ToFen())
+		}
+	}
+}
@@ -23,6 +23,13 @@ func Max32(x, y int32) int32 {
 	return y
 }
 
+func Min32(x, y int32) int32 {
+	if x < y {
+		return x
+	}
+	return y
+}
+
 func Max8(x, y int8) int8 {
 	if x > y {
 		return x
@@ -154,17 +154,42 @@ type Board struct {
 	// Zobrist hash key for the current position (for move repetition and hashing)
 	zobristKey uint64
 
+	// Zobrist keys restricted to the pawns, and to each side's other pieces
+	// (king included). Eval caches and correction history index by these.
+	pawnKey    uint64
+	nonPawnKey [2]uint64
+
+	// Number of pieces of each kind, and a Zobrist key of those counts that
+	// identifies the material balance (indexes the material hash).
+	pieceCount  [15]uint8
+	materialKey uint64
+
+	// Running material + piece-square sums against psqt (see psqt.go).
+	psqt           *PSQTables
+	psqtMG, psqtEG int32
+
 	// Aggregated bitboards and turn flag for consumers.
 	White   Bitboards
 	Black   Bitboards
 	Wtomove bool
 }
 
-// HasLegalMoves reports whether the side to move has any legal moves.
+// HasLegalMoves reports whether the side to move has any legal moves. A safe
+// king step answers it without generating the other moves.
 func (b *Board) HasLegalMoves() bool {
-	buf := make([]Move, 0, 64)
-	moves := b.GenerateMovesInto(buf)
-	return len(moves) > 0
+	us := int(b.sideToMove)
+	if kbb := b.kings[us]; kbb != 0 {
+		from := bits.TrailingZeros64(kbb)
+		occ := (b.occupancy[0] | b.occupancy[1]) &^ kbb
+		for t := kingMoves[from] &^ b.occupancy[us]; t != 0; {
+			to := popLSB(&t)
+			if !b.isSquareAttackedWithOcc(to, Color(1-us), occ|uint64(1)<<uint(to)) {
+				return true
+			}
+		}
+	}
+	var buf [256]Move
+	return len(b.GenerateMovesInto(buf[:0])) > 0
 }
 
 // InCheckmate reports whether the side to move is checkmated.
@@ -188,6 +213,9 @@ func (b *Board) HalfmoveClock() int { return b.halfmoveClock }
 // FullmoveNumber returns the full move counter (incremented after Black's move).
 func (b *Board) FullmoveNumber() int { return b.fullmoveNumber }
 
+// CastlingRights returns the castling rights still available to both sides.
+func (b *Board) CastlingRights() CastlingRights { return b.castlingRights }
+
 // EnPassantSquare returns the current en-passant target square or NoSquare.
 func (b *Board) EnPassantSquare() Square { return b.enPassantSquare }
 
@@ -206,6 +234,18 @@ func (b *Board) SetSideToMove(c Color) {
 // Hash returns the current Zobrist hash key.
 func (b *Board) Hash() uint64 { return b.zobristKey }
 
+// PawnKey returns the Zobrist key of the pawn structure (both colors).
+func (b *Board) PawnKey() uint64 { return b.pawnKey }
+
+// NonPawnKey returns the Zobrist key of c's pieces other than pawns.
+func (b *Board) NonPawnKey(c Color) uint64 { return b.nonPawnKey[c] }
+
+// MaterialKey returns the Zobrist key of the piece counts of both colors.
+func (b *Board) MaterialKey() uint64 { return b.materialKey }
+
+// PieceCount returns the number of pieces p on the board.
+func (b *Board) PieceCount(p Piece) int { return int(b.pieceCount[p]) }
+
 // Bitboards returns the per-piece bitboards for the requested side.
 func (b *Board) Bitboards(color Color) Bitboards {
 	idx := int(color)
@@ -375,7 +415,9 @@ func (b *Board) addPiece(sq Square, p Piece) {
 		b.kings[ci] |= bb(sq)
 	}
 	// Zobrist: XOR in piece on square
-	b.zobristKey ^= zobristPiece[p][idx]
+	b.xorPieceKeys(p, idx)
+	b.addMaterial(p)
+	b.addPSQT(p, idx)
 }
 
 // removePiece removes a piece from a square and updates bitboards, occupancy and zobrist.
@@ -405,7 +447,9 @@ func (b *Board) removePiece(sq Square) Piece {
 		b.kings[ci] &= mask
 	}
 	// Zobrist: XOR out piece on square
-	b.zobristKey ^= zobristPiece[p][idx]
+	b.xorPieceKeys(p, idx)
+	b.removeMaterial(p)
+	b.removePSQT(p, idx)
 	return p
 }
 
@@ -465,5 +509,14 @@ func (b *Board) Validate() bool {
 	if b.zobristKey != b.ComputeZobrist() {
 		return false
 	}
+	if b.pawnKey != b.ComputePawnKey() || b.nonPawnKey != b.ComputeNonPawnKeys() {
+		return false
+	}
+	if b.pieceCount != b.countPieces() || b.materialKey != b.ComputeMaterialKey() {
+		return false
+	}
+	if mg, eg := b.ComputePSQT(); b.psqt != nil && (mg != b.psqtMG || eg != b.psqtEG) {
+		return false
+	}
 	return true
 }
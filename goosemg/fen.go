@@ -212,6 +212,11 @@ func ParseFEN(fen string) (*Board, error) {
 
 	// Compute initial Zobrist hash for this position
 	board.zobristKey = board.ComputeZobrist()
+	board.pawnKey = board.ComputePawnKey()
+	board.nonPawnKey = board.ComputeNonPawnKeys()
+	board.pieceCount = board.countPieces()
+	board.materialKey = board.ComputeMaterialKey()
+	board.RefreshPSQT()
 	return board, nil
 }
 
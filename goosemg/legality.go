@@ -0,0 +1,213 @@
+package goosemg
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// IsPseudoLegal reports whether m could have been produced by the move
+// generator for the current position, ignoring whether it leaves the mover's
+// king in check. Every field of the encoding (moved piece, captured piece,
+// promotion, flags) must match the board, so moves taken from another
+// position (TT moves after a hash collision, killers, counter moves) are
+// rejected safely.
+func (b *Board) IsPseudoLegal(m Move) bool {
+	if m == 0 {
+		return false
+	}
+	side := b.sideToMove
+	us := int(side)
+	them := 1 - us
+	from := int(m.From())
+	to := int(m.To())
+	moved := m.MovedPiece()
+	captured := m.CapturedPiece()
+	promo := m.PromotionPiece()
+	flag := m.Flags()
+
+	if from == to || moved == NoPiece || b.pieces[from] != moved || colorOf(moved) != side {
+		return false
+	}
+	toBB := uint64(1) << uint(to)
+	if b.occupancy[us]&toBB != 0 {
+		return false
+	}
+	allOcc := b.occupancy[0] | b.occupancy[1]
+
+	switch flag {
+	case FlagCastle:
+		return moved.Type() == PieceTypeKing && captured == NoPiece && promo == NoPiece && b.castlePathClear(from, to)
+	case FlagEnPassant:
+		return moved.Type() == PieceTypePawn && promo == NoPiece &&
+			b.enPassantSquare == Square(to) && pawnAttacks[us][from]&toBB != 0 &&
+			captured == PieceFromType(Color(them), PieceTypePawn)
+	case FlagNone:
+	default:
+		return false
+	}
+
+	// Ordinary moves: the captured piece must be exactly what stands on the
+	// target square, and kings are never captured.
+	if b.pieces[to] != captured {
+		return false
+	}
+	if captured != NoPiece && captured.Type() == PieceTypeKing {
+		return false
+	}
+
+	switch moved.Type() {
+	case PieceTypePawn:
+		lastRank := (side == White && to/8 == 7) || (side == Black && to/8 == 0)
+		if lastRank != (promo != NoPiece) {
+			return false
+		}
+		if promo != NoPiece {
+			pt := promo.Type()
+			if colorOf(promo) != side || pt < PieceTypeKnight || pt > PieceTypeQueen {
+				return false
+			}
+		}
+		if captured != NoPiece {
+			return pawnAttacks[us][from]&toBB != 0
+		}
+		push := 8
+		startRank := 1
+		if side == Black {
+			push = -8
+			startRank = 6
+		}
+		if to == from+push {
+			return true
+		}
+		return to == from+2*push && from/8 == startRank && b.pieces[from+push] == NoPiece
+	case PieceTypeKnight:
+		return promo == NoPiece && knightMoves[from]&toBB != 0
+	case PieceTypeBishop:
+		return promo == NoPiece && bishopAttacksMagic(from, allOcc)&toBB != 0
+	case PieceTypeRook:
+		return promo == NoPiece && rookAttacksMagic(from, allOcc)&toBB != 0
+	case PieceTypeQueen:
+		return promo == NoPiece && (rookAttacksMagic(from, allOcc)|bishopAttacksMagic(from, allOcc))&toBB != 0
+	case PieceTypeKing:
+		return promo == NoPiece && kingMoves[from]&toBB != 0
+	}
+	return false
+}
+
+// castlePathClear checks the castling rights, rook placement and empty squares
+// for a castling move from -> to. Attacks on the path are left to IsLegal.
+func (b *Board) castlePathClear(from, to int) bool {
+	if b.sideToMove == White {
+		switch {
+		case from == 4 && to == 6:
+			return b.castlingRights&CastlingWhiteK != 0 && b.pieces[7] == WhiteRook &&
+				b.pieces[5] == NoPiece && b.pieces[6] == NoPiece
+		case from == 4 && to == 2:
+			return b.castlingRights&CastlingWhiteQ != 0 && b.pieces[0] == WhiteRook &&
+				b.pieces[1] == NoPiece && b.pieces[2] == NoPiece && b.pieces[3] == NoPiece
+		}
+		return false
+	}
+	switch {
+	case from == 60 && to == 62:
+		return b.castlingRights&CastlingBlackK != 0 && b.pieces[63] == BlackRook &&
+			b.pieces[61] == NoPiece && b.pieces[62] == NoPiece
+	case from == 60 && to == 58:
+		return b.castlingRights&CastlingBlackQ != 0 && b.pieces[56] == BlackRook &&
+			b.pieces[57] == NoPiece && b.pieces[58] == NoPiece && b.pieces[59] == NoPiece
+	}
+	return false
+}
+
+// IsLegal reports whether a pseudo-legal move leaves the mover's king safe.
+// Callers must check IsPseudoLegal first; the result is undefined otherwise.
+// Ordinary moves are decided from the check and pin masks of
+// computeCheckAndPins, so the board is never modified.
+func (b *Board) IsLegal(m Move) bool {
+	side := b.sideToMove
+	us := int(side)
+	them := Color(1 - us)
+	kingBB := b.kings[us]
+	if kingBB == 0 {
+		return false
+	}
+	ks := bits.TrailingZeros64(kingBB)
+	from, to := int(m.From()), int(m.To())
+	fromBB := uint64(1) << uint(from)
+	toBB := uint64(1) << uint(to)
+	occ := b.occupancy[0] | b.occupancy[1]
+
+	switch {
+	case m.Flags() == FlagCastle:
+		// The king may not castle out of, through, or into check
+		step := 1
+		if to < from {
+			step = -1
+		}
+		for sq := from; sq != to+step; sq += step {
+			if b.isSquareAttackedWithOcc(sq, them, occ) {
+				return false
+			}
+		}
+		return true
+
+	case from == ks:
+		// Lift the king off the board so sliders see through its old square
+		return !b.isSquareAttackedWithOcc(to, them, occ&^fromBB)
+
+	case m.Flags() == FlagEnPassant:
+		// Two pawns leave the king's rank or diagonal at once, which the pin
+		// masks can't express; test the resulting occupancy directly.
+		capSq := to - 8
+		if side == Black {
+			capSq = to + 8
+		}
+		capBB := uint64(1) << uint(capSq)
+		after := occ&^fromBB&^capBB | toBB
+		return !b.isSquareAttackedWithOccAndPawns(ks, them, after, b.pawns[int(them)]&^capBB)
+	}
+
+	inCheck, doubleCheck, checkMask, pinLine := b.computeCheckAndPins(side, occ)
+	if doubleCheck {
+		return false
+	}
+	if inCheck && toBB&checkMask == 0 {
+		return false
+	}
+	return pinLine[from] == 0 || toBB&pinLine[from] != 0
+}
+
+// ParseUCIMove resolves a coordinate move (e2e4, e7e8q, e1g1) against the
+// current position and returns it only if it is legal.
+func (b *Board) ParseUCIMove(s string) (Move, error) {
+	parsed, err := ParseMove(s)
+	if err != nil {
+		return 0, err
+	}
+	if parsed == 0 {
+		return 0, fmt.Errorf("null move %q", s)
+	}
+	from, to := parsed.From(), parsed.To()
+	moved := b.pieces[from]
+	if moved == NoPiece {
+		return 0, fmt.Errorf("no piece on %s", squareName(from))
+	}
+	captured := b.pieces[to]
+	var promo Piece
+	if parsed.PromotionPiece() != NoPiece {
+		promo = PieceFromType(b.sideToMove, parsed.PromotionPieceType())
+	}
+	flag := uint8(FlagNone)
+	switch {
+	case moved.Type() == PieceTypeKing && (int(to)-int(from) == 2 || int(from)-int(to) == 2):
+		flag = FlagCastle
+	case moved.Type() == PieceTypePawn && to == b.enPassantSquare && captured == NoPiece && (int(to)-int(from))%8 != 0:
+		flag = FlagEnPassant
+		captured = PieceFromType(1-b.sideToMove, PieceTypePawn)
+	}
+	m := NewMove(from, to, moved, captured, promo, flag)
+	if !b.IsPseudoLegal(m) || !b.IsLegal(m) {
+		return 0, fmt.Errorf("illegal move %q", s)
+	}
+	return m, nil
+}
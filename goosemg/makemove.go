@@ -11,6 +11,11 @@ type MoveState struct {
 	prevHalfmove  int
 	prevFullmove  int
 	prevZobrist   uint64
+	prevPawnKey   uint64
+	prevNonPawn   [2]uint64
+	prevMaterial  uint64
+	prevPSQTMG    int32
+	prevPSQTEG    int32
 	rookFrom      Square // for castling undo
 	rookTo        Square // for castling undo
 }
@@ -40,6 +45,10 @@ func (b *Board) MakeMove(m Move) (ok bool, st MoveState) {
 	st.prevHalfmove = b.halfmoveClock
 	st.prevFullmove = b.fullmoveNumber
 	st.prevZobrist = b.zobristKey
+	st.prevPawnKey = b.pawnKey
+	st.prevNonPawn = b.nonPawnKey
+	st.prevMaterial = b.materialKey
+	st.prevPSQTMG, st.prevPSQTEG = b.psqtMG, b.psqtEG
 	st.rookFrom, st.rookTo = NoSquare, NoSquare
 	st.captured = NoPiece
 
@@ -81,7 +90,9 @@ func (b *Board) MakeMove(m Move) (ok bool, st MoveState) {
 		b.pieces[int(capSq)] = NoPiece
 		b.occupancy[them] &^= capBB
 		b.pawns[them] &^= capBB
-		b.zobristKey ^= zobristPiece[capPiece][int(capSq)]
+		b.xorPieceKeys(capPiece, int(capSq))
+		b.removeMaterial(capPiece)
+		b.removePSQT(capPiece, int(capSq))
 	} else if captured != NoPiece {
 		// Remove captured piece at 'to'
 		st.captured = captured
@@ -101,7 +112,9 @@ func (b *Board) MakeMove(m Move) (ok bool, st MoveState) {
 		case 6:
 			b.kings[them] &^= toBB
 		}
-		b.zobristKey ^= zobristPiece[captured][int(to)]
+		b.xorPieceKeys(captured, int(to))
+		b.removeMaterial(captured)
+		b.removePSQT(captured, int(to))
 	}
 
 	// Move the piece (or promote)
@@ -110,7 +123,7 @@ func (b *Board) MakeMove(m Move) (ok bool, st MoveState) {
 		b.pieces[int(from)] = NoPiece
 		b.occupancy[us] &^= fromBB
 		b.pawns[us] &^= fromBB
-		b.zobristKey ^= zobristPiece[moved][int(from)]
+		b.xorPieceKeys(moved, int(from))
 		// Add promoted piece at to
 		b.pieces[int(to)] = promo
 		b.occupancy[us] |= toBB
@@ -126,7 +139,11 @@ func (b *Board) MakeMove(m Move) (ok bool, st MoveState) {
 		case 6:
 			b.kings[us] |= toBB
 		}
-		b.zobristKey ^= zobristPiece[promo][int(to)]
+		b.xorPieceKeys(promo, int(to))
+		b.removeMaterial(moved)
+		b.addMaterial(promo)
+		b.removePSQT(moved, int(from))
+		b.addPSQT(promo, int(to))
 	} else {
 		// Quiet move of the piece from -> to
 		b.pieces[int(from)] = NoPiece
@@ -147,8 +164,9 @@ func (b *Board) MakeMove(m Move) (ok bool, st MoveState) {
 			b.kings[us] ^= (fromBB | toBB)
 		}
 		// Zobrist piece move
-		b.zobristKey ^= zobristPiece[moved][int(from)]
-		b.zobristKey ^= zobristPiece[moved][int(to)]
+		b.xorPieceKeys(moved, int(from))
+		b.xorPieceKeys(moved, int(to))
+		b.movePSQT(moved, int(from), int(to))
 	}
 
 	// Castling rook movement
@@ -162,8 +180,9 @@ func (b *Board) MakeMove(m Move) (ok bool, st MoveState) {
 				nb := uint64(1) << 5
 				b.occupancy[us] ^= (rb | nb)
 				b.rooks[us] ^= (rb | nb)
-				b.zobristKey ^= zobristPiece[WhiteRook][7]
-				b.zobristKey ^= zobristPiece[WhiteRook][5]
+				b.xorPieceKeys(WhiteRook, 7)
+				b.xorPieceKeys(WhiteRook, 5)
+				b.movePSQT(WhiteRook, 7, 5)
 				st.rookFrom, st.rookTo = 7, 5
 			} else if to == 2 { // c1
 				b.pieces[0] = NoPiece
@@ -172,8 +191,9 @@ func (b *Board) MakeMove(m Move) (ok bool, st MoveState) {
 				nb := uint64(1) << 3
 				b.occupancy[us] ^= (rb | nb)
 				b.rooks[us] ^= (rb | nb)
-				b.zobristKey ^= zobristPiece[WhiteRook][0]
-				b.zobristKey ^= zobristPiece[WhiteRook][3]
+				b.xorPieceKeys(WhiteRook, 0)
+				b.xorPieceKeys(WhiteRook, 3)
+				b.movePSQT(WhiteRook, 0, 3)
 				st.rookFrom, st.rookTo = 0, 3
 			}
 		} else if moved == BlackKing {
@@ -184,8 +204,9 @@ func (b *Board) MakeMove(m Move) (ok bool, st MoveState) {
 				nb := uint64(1) << 61
 				b.occupancy[us] ^= (rb | nb)
 				b.rooks[us] ^= (rb | nb)
-				b.zobristKey ^= zobristPiece[BlackRook][63]
-				b.zobristKey ^= zobristPiece[BlackRook][61]
+				b.xorPieceKeys(BlackRook, 63)
+				b.xorPieceKeys(BlackRook, 61)
+				b.movePSQT(BlackRook, 63, 61)
 				st.rookFrom, st.rookTo = 63, 61
 			} else if to == 58 { // c8
 				b.pieces[56] = NoPiece
@@ -194,8 +215,9 @@ func (b *Board) MakeMove(m Move) (ok bool, st MoveState) {
 				nb := uint64(1) << 59
 				b.occupancy[us] ^= (rb | nb)
 				b.rooks[us] ^= (rb | nb)
-				b.zobristKey ^= zobristPiece[BlackRook][56]
-				b.zobristKey ^= zobristPiece[BlackRook][59]
+				b.xorPieceKeys(BlackRook, 56)
+				b.xorPieceKeys(BlackRook, 59)
+				b.movePSQT(BlackRook, 56, 59)
 				st.rookFrom, st.rookTo = 56, 59
 			}
 		}
@@ -370,6 +392,8 @@ func (b *Board) UnmakeMove(m Move, st MoveState) {
 			b.kings[us] &^= toBB
 		}
 		b.pawns[us] |= fromBB
+		b.pieceCount[pawn]++
+		b.pieceCount[promo]--
 	} else {
 		// Move piece back to from
 		b.pieces[int(from)] = moved
@@ -392,6 +416,7 @@ func (b *Board) UnmakeMove(m Move, st MoveState) {
 
 	// Restore captured piece
 	if st.captured != NoPiece {
+		b.pieceCount[st.captured]++
 		if flag == FlagEnPassant {
 			var capSq Square
 			if moved&8 == 0 { // white moved originally
@@ -442,6 +467,10 @@ func (b *Board) UnmakeMove(m Move, st MoveState) {
 
 	// Ensure exact Zobrist restoration
 	b.zobristKey = st.prevZobrist
+	b.pawnKey = st.prevPawnKey
+	b.nonPawnKey = st.prevNonPawn
+	b.materialKey = st.prevMaterial
+	b.psqtMG, b.psqtEG = st.prevPSQTMG, st.prevPSQTEG
 	b.refreshBitboards()
 }
 
@@ -483,6 +512,7 @@ func (b *Board) UnmakeNullMove(st NullState) {
 	b.halfmoveClock = st.prevHalfmove
 	b.fullmoveNumber = st.prevFullmove
 	b.sideToMove = st.prevSide
+	b.Wtomove = b.sideToMove == White
 	// Ensure exact Zobrist restoration
 	b.zobristKey = st.prevZobrist
 }
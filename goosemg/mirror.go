@@ -0,0 +1,63 @@
+package goosemg
+
+// FlipColors returns the position seen from the other side: the board is
+// flipped vertically (a1 <-> a8), every piece changes color, and the side to
+// move, castling rights and en passant square follow. A color-symmetric
+// evaluation scores the result the same for the side to move. Move history
+// is not carried over.
+func (b *Board) FlipColors() Board {
+	var cr CastlingRights
+	if b.castlingRights&CastlingWhiteK != 0 {
+		cr |= CastlingBlackK
+	}
+	if b.castlingRights&CastlingWhiteQ != 0 {
+		cr |= CastlingBlackQ
+	}
+	if b.castlingRights&CastlingBlackK != 0 {
+		cr |= CastlingWhiteK
+	}
+	if b.castlingRights&CastlingBlackQ != 0 {
+		cr |= CastlingWhiteQ
+	}
+	return b.transformed(func(sq int) int { return sq ^ 56 }, true, cr)
+}
+
+// Mirror returns the position reflected horizontally (a-file <-> h-file)
+// with colors and side to move unchanged. Castling has no mirror image, so
+// the rights are dropped; only positions without castling rights should be
+// expected to evaluate the same as their mirror.
+func (b *Board) Mirror() Board {
+	return b.transformed(func(sq int) int { return sq ^ 7 }, false, 0)
+}
+
+// transformed builds a fresh board with every piece moved to mapSq(sq),
+// swapping colors (and the side to move) when swap is set.
+func (b *Board) transformed(mapSq func(int) int, swap bool, cr CastlingRights) Board {
+	var nb Board
+	for sq := 0; sq < 64; sq++ {
+		p := b.pieces[sq]
+		if p == NoPiece {
+			continue
+		}
+		if swap {
+			p ^= 8
+		}
+		nb.addPiece(Square(mapSq(sq)), p)
+	}
+	nb.sideToMove = b.sideToMove
+	if swap {
+		nb.sideToMove = 1 - b.sideToMove
+	}
+	nb.castlingRights = cr
+	nb.enPassantSquare = NoSquare
+	if b.enPassantSquare != NoSquare {
+		nb.enPassantSquare = Square(mapSq(int(b.enPassantSquare)))
+	}
+	nb.halfmoveClock = b.halfmoveClock
+	nb.fullmoveNumber = b.fullmoveNumber
+
+	nb.refreshBitboards()
+	nb.zobristKey = nb.ComputeZobrist()
+	nb.RefreshPSQT()
+	return nb
+}
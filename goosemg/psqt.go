@@ -0,0 +1,85 @@
+package goosemg
+
+import "fmt"
+
+// PSQTables holds a combined material and piece-square score for every piece
+// on every square, split into middlegame and endgame halves. Entries are from
+// white's point of view, so black's pieces carry negative scores. The engine
+// installs its tables with SetPSQTables; boards then keep a running sum that
+// addPiece, removePiece and MakeMove update as pieces move.
+type PSQTables struct {
+	MG [15][64]int32
+	EG [15][64]int32
+}
+
+// psqtTables is the table set new boards accumulate against (nil: none).
+var psqtTables *PSQTables
+
+// VerifyPSQT makes PSQT check the incremental sums against a full recount and
+// panic on a mismatch. Debug aid; it makes every lookup O(pieces).
+var VerifyPSQT bool
+
+// SetPSQTables installs t as the tables that boards created from now on (and
+// boards calling RefreshPSQT) accumulate against. nil turns the sums off.
+func SetPSQTables(t *PSQTables) { psqtTables = t }
+
+// PSQT returns the incrementally maintained material + piece-square sums.
+// ok is false when the board was not set up against the installed tables (a
+// zero Board, or tables installed after the board was created); callers then
+// have to compute the score themselves.
+func (b *Board) PSQT() (mg, eg int32, ok bool) {
+	if b.psqt == nil || b.psqt != psqtTables {
+		return 0, 0, false
+	}
+	if VerifyPSQT {
+		if fullMG, fullEG := b.ComputePSQT(); fullMG != b.psqtMG || fullEG != b.psqtEG {
+			panic(fmt.Sprintf("goosemg: incremental PSQT (%d, %d) != recount (%d, %d) in %s",
+				b.psqtMG, b.psqtEG, fullMG, fullEG, b.ToFEN()))
+		}
+	}
+	return b.psqtMG, b.psqtEG, true
+}
+
+// RefreshPSQT binds the board to the installed tables and recomputes the sums
+// from scratch.
+func (b *Board) RefreshPSQT() {
+	b.psqt = psqtTables
+	b.psqtMG, b.psqtEG = b.ComputePSQT()
+}
+
+// ComputePSQT calculates the material + piece-square sums from scratch
+// against the board's tables.
+func (b *Board) ComputePSQT() (mg, eg int32) {
+	if b.psqt == nil {
+		return 0, 0
+	}
+	for sq := 0; sq < 64; sq++ {
+		if p := b.pieces[sq]; p != NoPiece {
+			mg += b.psqt.MG[p][sq]
+			eg += b.psqt.EG[p][sq]
+		}
+	}
+	return mg, eg
+}
+
+// addPSQT, removePSQT and movePSQT keep the sums in step with the pieces.
+func (b *Board) addPSQT(p Piece, sq int) {
+	if t := b.psqt; t != nil {
+		b.psqtMG += t.MG[p][sq]
+		b.psqtEG += t.EG[p][sq]
+	}
+}
+
+func (b *Board) removePSQT(p Piece, sq int) {
+	if t := b.psqt; t != nil {
+		b.psqtMG -= t.MG[p][sq]
+		b.psqtEG -= t.EG[p][sq]
+	}
+}
+
+func (b *Board) movePSQT(p Piece, from, to int) {
+	if t := b.psqt; t != nil {
+		b.psqtMG += t.MG[p][to] - t.MG[p][from]
+		b.psqtEG += t.EG[p][to] - t.EG[p][from]
+	}
+}
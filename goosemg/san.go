@@ -0,0 +1,203 @@
+package goosemg
+
+import (
+	"errors"
+	"strings"
+)
+
+// sanPieceLetter maps piece types to their SAN letters (pawns have none).
+var sanPieceLetter = [7]string{"", "", "N", "B", "R", "Q", "K"}
+
+// SAN returns the Standard Algebraic Notation of a legal move in the current
+// position, including the check (+) or mate (#) suffix.
+func (b *Board) SAN(m Move) string {
+	var sb strings.Builder
+	from := m.From()
+	to := m.To()
+	pt := m.MovedPiece().Type()
+	isCapture := m.CapturedPiece() != NoPiece || m.Flags() == FlagEnPassant
+
+	switch {
+	case m.Flags() == FlagCastle:
+		if to%8 == 6 {
+			sb.WriteString("O-O")
+		} else {
+			sb.WriteString("O-O-O")
+		}
+	case pt == PieceTypePawn:
+		if isCapture {
+			sb.WriteByte('a' + byte(from%8))
+			sb.WriteByte('x')
+		}
+		sb.WriteString(squareName(to))
+		if promo := m.PromotionPieceType(); promo != PieceTypeNone {
+			sb.WriteByte('=')
+			sb.WriteString(sanPieceLetter[promo])
+		}
+	default:
+		sb.WriteString(sanPieceLetter[pt])
+		sb.WriteString(b.sanDisambiguation(m))
+		if isCapture {
+			sb.WriteByte('x')
+		}
+		sb.WriteString(squareName(to))
+	}
+
+	ok, st := b.MakeMove(m)
+	if ok {
+		if b.InCheck(b.sideToMove) {
+			if b.HasLegalMoves() {
+				sb.WriteByte('+')
+			} else {
+				sb.WriteByte('#')
+			}
+		}
+		b.UnmakeMove(m, st)
+	}
+	return sb.String()
+}
+
+// sanDisambiguation returns the file, rank or square needed to tell m apart
+// from other legal moves of the same piece type to the same square.
+func (b *Board) sanDisambiguation(m Move) string {
+	from := m.From()
+	sameFile, sameRank, others := false, false, false
+	for _, other := range b.GenerateMoves() {
+		if other == m || other.To() != m.To() || other.MovedPiece() != m.MovedPiece() || other.From() == from {
+			continue
+		}
+		others = true
+		if other.From()%8 == from%8 {
+			sameFile = true
+		}
+		if other.From()/8 == from/8 {
+			sameRank = true
+		}
+	}
+	switch {
+	case !others:
+		return ""
+	case !sameFile:
+		return string([]byte{'a' + byte(from%8)})
+	case !sameRank:
+		return string([]byte{'1' + byte(from/8)})
+	default:
+		return squareName(from)
+	}
+}
+
+// ParseSAN returns the legal move described by a SAN string. Check, mate and
+// annotation suffixes are ignored, "0-0" is accepted for castling, and
+// over-specified disambiguation (e.g. "Ng1f3") is tolerated.
+func (b *Board) ParseSAN(san string) (Move, error) {
+	s := strings.TrimRight(strings.TrimSpace(san), "+#!?")
+	if s == "" {
+		return 0, errors.New("empty SAN move")
+	}
+	legal := b.GenerateMoves()
+
+	switch strings.ReplaceAll(s, "0", "O") {
+	case "O-O", "O-O-O":
+		queenSide := len(s) == 5
+		for _, m := range legal {
+			if m.Flags() == FlagCastle && (m.To()%8 == 2) == queenSide {
+				return m, nil
+			}
+		}
+		return 0, errors.New("castling is not legal: " + san)
+	}
+
+	pt := PieceTypePawn
+	switch s[0] {
+	case 'N':
+		pt = PieceTypeKnight
+	case 'B':
+		pt = PieceTypeBishop
+	case 'R':
+		pt = PieceTypeRook
+	case 'Q':
+		pt = PieceTypeQueen
+	case 'K':
+		pt = PieceTypeKing
+	}
+	if pt != PieceTypePawn {
+		s = s[1:]
+	}
+
+	promo := PieceTypeNone
+	if i := strings.IndexByte(s, '='); i >= 0 {
+		if i+1 >= len(s) {
+			return 0, errors.New("missing promotion piece: " + san)
+		}
+		promo = sanPromotionType(s[i+1])
+		s = s[:i]
+	} else if pt == PieceTypePawn && len(s) > 2 {
+		if p := sanPromotionType(s[len(s)-1]); p != PieceTypeNone {
+			promo = p
+			s = s[:len(s)-1]
+		}
+	}
+
+	s = strings.NewReplacer("x", "", ":", "", "-", "").Replace(s)
+	if len(s) < 2 {
+		return 0, errors.New("invalid SAN move: " + san)
+	}
+	to, err := algebraicToIndex(s[len(s)-2:])
+	if err != nil {
+		return 0, errors.New("invalid SAN destination: " + san)
+	}
+
+	fromFile, fromRank := -1, -1
+	for _, ch := range s[:len(s)-2] {
+		switch {
+		case ch >= 'a' && ch <= 'h':
+			fromFile = int(ch - 'a')
+		case ch >= '1' && ch <= '8':
+			fromRank = int(ch - '1')
+		default:
+			return 0, errors.New("invalid SAN disambiguation: " + san)
+		}
+	}
+
+	var match Move
+	found := 0
+	for _, m := range legal {
+		if m.MovedPiece().Type() != pt || int(m.To()) != to || m.PromotionPieceType() != promo || m.Flags() == FlagCastle {
+			continue
+		}
+		if fromFile >= 0 && int(m.From()%8) != fromFile {
+			continue
+		}
+		if fromRank >= 0 && int(m.From()/8) != fromRank {
+			continue
+		}
+		match = m
+		found++
+	}
+	switch found {
+	case 0:
+		return 0, errors.New("no legal move matches SAN: " + san)
+	case 1:
+		return match, nil
+	default:
+		return 0, errors.New("ambiguous SAN move: " + san)
+	}
+}
+
+func sanPromotionType(ch byte) PieceType {
+	switch ch {
+	case 'Q', 'q':
+		return PieceTypeQueen
+	case 'R', 'r':
+		return PieceTypeRook
+	case 'B', 'b':
+		return PieceTypeBishop
+	case 'N', 'n':
+		return PieceTypeKnight
+	}
+	return PieceTypeNone
+}
+
+func squareName(sq Square) string {
+	return string([]byte{'a' + byte(sq%8), '1' + byte(sq/8)})
+}
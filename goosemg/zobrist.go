@@ -2,11 +2,12 @@ package goosemg
 
 import "math/rand"
 
-// Zobrist hashing tables for pieces, castling, en passant, and side to move.
-var zobristPiece [15][64]uint64 // Zobrist keys for piece (index by piece code) on each square
-var zobristCastle [16]uint64    // Zobrist keys for each castling rights state (0-15)
-var zobristEnPassant [8]uint64  // Zobrist keys for en passant file (file 0-7)
-var zobristSide uint64          // Zobrist key for side to move (Black to move)
+// Zobrist hashing tables for pieces, castling, en passant, side to move and material.
+var zobristPiece [15][64]uint64     // Zobrist keys for piece (index by piece code) on each square
+var zobristCastle [16]uint64        // Zobrist keys for each castling rights state (0-15)
+var zobristEnPassant [8]uint64      // Zobrist keys for en passant file (file 0-7)
+var zobristSide uint64              // Zobrist key for side to move (Black to move)
+var zobristMaterial [15][16]uint64  // Zobrist keys for the n-th piece of each kind (material key)
 
 // Initialize Zobrist keys (called on package init)
 func init() {
@@ -36,6 +37,13 @@ func initZobrist() {
 
     // Side to move key
     zobristSide = rnd.Uint64()
+
+    // Material keys
+    for p := 0; p < 15; p++ {
+        for n := 0; n < 16; n++ {
+            zobristMaterial[p][n] = rnd.Uint64()
+        }
+    }
 }
 
 // ComputeZobrist calculates the Zobrist hash for the current board state.
@@ -66,3 +74,74 @@ func (b *Board) ComputeZobrist() uint64 {
 
     return key
 }
+
+// ComputePawnKey calculates the Zobrist key of the pawns alone.
+func (b *Board) ComputePawnKey() uint64 {
+    var key uint64
+    for sq := 0; sq < 64; sq++ {
+        p := b.pieces[sq]
+        if p != NoPiece && typeOf(p) == 1 {
+            key ^= zobristPiece[p][sq]
+        }
+    }
+    return key
+}
+
+// ComputeNonPawnKeys calculates, per color, the Zobrist key of all pieces
+// except pawns.
+func (b *Board) ComputeNonPawnKeys() [2]uint64 {
+    var keys [2]uint64
+    for sq := 0; sq < 64; sq++ {
+        p := b.pieces[sq]
+        if p != NoPiece && typeOf(p) != 1 {
+            keys[colorOf(p)] ^= zobristPiece[p][sq]
+        }
+    }
+    return keys
+}
+
+// xorPieceKeys toggles piece p on square sq in the position key and in the
+// pawn or non-pawn key it belongs to.
+func (b *Board) xorPieceKeys(p Piece, sq int) {
+    k := zobristPiece[p][sq]
+    b.zobristKey ^= k
+    if typeOf(p) == 1 {
+        b.pawnKey ^= k
+    } else {
+        b.nonPawnKey[colorOf(p)] ^= k
+    }
+}
+
+// countPieces counts the pieces of each kind from scratch.
+func (b *Board) countPieces() (counts [15]uint8) {
+    for sq := 0; sq < 64; sq++ {
+        if p := b.pieces[sq]; p != NoPiece {
+            counts[p]++
+        }
+    }
+    return counts
+}
+
+// ComputeMaterialKey calculates the material key from scratch.
+func (b *Board) ComputeMaterialKey() uint64 {
+    var key uint64
+    counts := b.countPieces()
+    for p := range counts {
+        for n := 0; n < int(counts[p]); n++ {
+            key ^= zobristMaterial[p][n]
+        }
+    }
+    return key
+}
+
+// addMaterial and removeMaterial keep the piece counts and the material key
+// in step when a piece enters or leaves the board.
+func (b *Board) addMaterial(p Piece) {
+    b.materialKey ^= zobristMaterial[p][b.pieceCount[p]]
+    b.pieceCount[p]++
+}
+
+func (b *Board) removeMaterial(p Piece) {
+    b.pieceCount[p]--
+    b.materialKey ^= zobristMaterial[p][b.pieceCount[p]]
+}
@@ -0,0 +1,147 @@
+package goose_engine_mg_test
+
+import (
+	"testing"
+
+	myengine "chess-engine/goosemg"
+)
+
+// Perft suite positions plus a few targeted legality edge cases.
+var legalityFENs = []string{
+	myengine.FENStartPos,
+	"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+	"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+	"r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1",
+	"rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+	"r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10",
+	// En passant exposing the king along the rank
+	"8/8/8/KPp4r/8/8/8/7k w - c6 0 2",
+	// En passant capturing the checking pawn
+	"8/8/8/2k5/3Pp3/8/8/4K3 b - d3 0 1",
+	// Castling through an attacked square and out of check
+	"r3k2r/8/8/8/8/8/5q2/R3K2R w KQkq - 0 1",
+	"r3k2r/8/8/8/8/5b2/8/R3K2R w KQkq - 0 1",
+	// Promotions with and without capture, pinned promoting pawn
+	"1n2k3/P1P5/8/8/8/8/8/4K3 w - - 0 1",
+	"4k3/8/8/8/8/8/1p6/B3K3 b - - 0 1",
+}
+
+// candidateMoves builds a superset of the pseudo-legal moves: every pseudo
+// move, plus from/to pairs for each of our pieces with plausible and
+// implausible encodings (wrong flags, promotions, captured pieces).
+func candidateMoves(b *myengine.Board) []myengine.Move {
+	cands := b.GeneratePseudoMoves()
+	side := b.SideToMove()
+	promos := []myengine.PieceType{myengine.PieceTypeNone, myengine.PieceTypeKnight, myengine.PieceTypeQueen, myengine.PieceTypeKing}
+	for from := myengine.Square(0); from < 64; from++ {
+		moved := b.PieceAt(from)
+		if moved == myengine.NoPiece || moved.Color() != side {
+			continue
+		}
+		for to := myengine.Square(0); to < 64; to++ {
+			captured := b.PieceAt(to)
+			for _, pt := range promos {
+				promo := myengine.NoPiece
+				if pt != myengine.PieceTypeNone {
+					promo = myengine.PieceFromType(side, pt)
+				}
+				for flag := uint8(0); flag < 4; flag++ {
+					cands = append(cands, myengine.NewMove(from, to, moved, captured, promo, flag))
+				}
+			}
+			if moved.Type() == myengine.PieceTypePawn {
+				theirPawn := myengine.PieceFromType(1-side, myengine.PieceTypePawn)
+				cands = append(cands, myengine.NewMove(from, to, moved, theirPawn, myengine.NoPiece, myengine.FlagEnPassant))
+			}
+			// Stale captured piece, as a TT move from another position would carry
+			if captured == myengine.NoPiece {
+				cands = append(cands, myengine.NewMove(from, to, moved, myengine.PieceFromType(1-side, myengine.PieceTypeKnight), myengine.NoPiece, myengine.FlagNone))
+			}
+		}
+	}
+	return cands
+}
+
+func moveSet(moves []myengine.Move) map[myengine.Move]bool {
+	set := make(map[myengine.Move]bool, len(moves))
+	for _, m := range moves {
+		set[m] = true
+	}
+	return set
+}
+
+// checkLegalityAgainstGenerator compares IsPseudoLegal/IsLegal with the move
+// generators on every node of a depth-limited tree.
+func checkLegalityAgainstGenerator(t *testing.T, b *myengine.Board, depth int) {
+	t.Helper()
+	pseudo := moveSet(b.GeneratePseudoMoves())
+	legalMoves := b.GenerateMoves()
+	legal := moveSet(legalMoves)
+	for _, m := range candidateMoves(b) {
+		isPseudo := b.IsPseudoLegal(m)
+		if isPseudo != pseudo[m] {
+			t.Fatalf("%s: IsPseudoLegal(%v flags=%d promo=%d cap=%d) = %v, generator says %v",
+				b.ToFEN(), m, m.Flags(), m.PromotionPiece(), m.CapturedPiece(), isPseudo, pseudo[m])
+		}
+		if isPseudo && b.IsLegal(m) != legal[m] {
+			t.Fatalf("%s: IsLegal(%v) = %v, generator says %v", b.ToFEN(), m, !legal[m], legal[m])
+		}
+	}
+	if depth <= 1 {
+		return
+	}
+	for _, m := range legalMoves {
+		ok, st := b.MakeMove(m)
+		if !ok {
+			t.Fatalf("%s: generated move %v rejected by MakeMove", b.ToFEN(), m)
+		}
+		checkLegalityAgainstGenerator(t, b, depth-1)
+		b.UnmakeMove(m, st)
+	}
+}
+
+func TestLegalityMatchesGenerator(t *testing.T) {
+	depth := 3
+	if testing.Short() {
+		depth = 2
+	}
+	for _, fen := range legalityFENs {
+		b, err := myengine.ParseFEN(fen)
+		if err != nil {
+			t.Fatalf("ParseFEN(%q): %v", fen, err)
+		}
+		checkLegalityAgainstGenerator(t, b, depth)
+	}
+}
+
+func TestParseUCIMove(t *testing.T) {
+	cases := []struct {
+		fen, move string
+		ok        bool
+		flag      uint8
+	}{
+		{myengine.FENStartPos, "e2e4", true, myengine.FlagNone},
+		{myengine.FENStartPos, "e2e5", false, 0},
+		{myengine.FENStartPos, "e7e5", false, 0},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "e1g1", true, myengine.FlagCastle},
+		{"r3k2r/8/8/8/8/8/5q2/R3K2R w KQkq - 0 1", "e1c1", false, 0},
+		{"8/8/8/2k5/3Pp3/8/8/4K3 b - d3 0 1", "e4d3", true, myengine.FlagEnPassant},
+		{"8/8/8/KPp4r/8/8/8/7k w - c6 0 2", "b5c6", false, 0},
+		{"1n2k3/P1P5/8/8/8/8/8/4K3 w - - 0 1", "a7b8n", true, myengine.FlagNone},
+		{"1n2k3/P1P5/8/8/8/8/8/4K3 w - - 0 1", "a7b8", false, 0},
+	}
+	for _, c := range cases {
+		b, err := myengine.ParseFEN(c.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := b.ParseUCIMove(c.move)
+		if (err == nil) != c.ok {
+			t.Errorf("%s %s: err=%v, want ok=%v", c.fen, c.move, err, c.ok)
+			continue
+		}
+		if c.ok && (m.String() != c.move || m.Flags() != c.flag) {
+			t.Errorf("%s %s: got %v flags %d", c.fen, c.move, m, m.Flags())
+		}
+	}
+}
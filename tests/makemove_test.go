@@ -119,3 +119,67 @@ func TestMakeUnmake_Castling(t *testing.T) {
 		t.Fatalf("zobrist mismatch after castling unmake")
 	}
 }
+
+func TestMakeUnmake_NullMoveRestoresSideView(t *testing.T) {
+	b, err := myengine.ParseFEN(myengine.FENStartPos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	startZ := b.Hash()
+	st := b.MakeNullMove()
+	if b.Wtomove {
+		t.Fatalf("Wtomove should be false after a white null move")
+	}
+	b.UnmakeNullMove(st)
+	if !b.Wtomove || b.SideToMove() != myengine.White {
+		t.Fatalf("side to move not restored after UnmakeNullMove: Wtomove=%v side=%v", b.Wtomove, b.SideToMove())
+	}
+	if b.Hash() != startZ {
+		t.Fatalf("zobrist mismatch after null move unmake")
+	}
+}
+
+// checkPieceKeys walks every legal line to the given depth and compares the
+// incrementally maintained pawn, non-pawn and material keys against a full
+// recompute.
+func checkPieceKeys(t *testing.T, b *myengine.Board, depth int) {
+	t.Helper()
+	if b.PawnKey() != b.ComputePawnKey() {
+		t.Fatalf("%s: pawn key out of sync", b.ToFEN())
+	}
+	nonPawn := b.ComputeNonPawnKeys()
+	if b.NonPawnKey(myengine.White) != nonPawn[0] || b.NonPawnKey(myengine.Black) != nonPawn[1] {
+		t.Fatalf("%s: non-pawn keys out of sync", b.ToFEN())
+	}
+	if b.MaterialKey() != b.ComputeMaterialKey() {
+		t.Fatalf("%s: material key out of sync", b.ToFEN())
+	}
+	if depth == 0 {
+		return
+	}
+	pawnKey, materialKey := b.PawnKey(), b.MaterialKey()
+	for _, m := range b.GenerateMoves() {
+		ok, st := b.MakeMove(m)
+		if !ok {
+			t.Fatalf("%s: generated move %v rejected", b.ToFEN(), m)
+		}
+		checkPieceKeys(t, b, depth-1)
+		b.UnmakeMove(m, st)
+		if b.PawnKey() != pawnKey || b.MaterialKey() != materialKey {
+			t.Fatalf("%s: pawn or material key not restored after %v", b.ToFEN(), m)
+		}
+		if !b.Validate() {
+			t.Fatalf("%s: board inconsistent after undoing %v", b.ToFEN(), m)
+		}
+	}
+}
+
+func TestMakeUnmake_PieceKeysIncremental(t *testing.T) {
+	for _, fen := range legalityFENs {
+		b, err := myengine.ParseFEN(fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkPieceKeys(t, b, 2)
+	}
+}
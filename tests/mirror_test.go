@@ -0,0 +1,73 @@
+package goose_engine_mg_test
+
+import (
+	myengine "chess-engine/goosemg"
+	"testing"
+)
+
+func TestFlipColors(t *testing.T) {
+	b, err := myengine.ParseFEN("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K1R1 w Qkq - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := b.FlipColors()
+	if want := "r3k1r1/pppbbppp/2n2q1P/1P2p3/3pn3/BN2PNP1/P1PPQPB1/R3K2R b KQq - 0 1"; f.ToFEN() != want {
+		t.Errorf("flipped FEN %q, want %q", f.ToFEN(), want)
+	}
+	if !f.Validate() {
+		t.Errorf("flipped board invariants invalid")
+	}
+	if back := f.FlipColors(); back.ToFEN() != b.ToFEN() || back.Hash() != b.Hash() {
+		t.Errorf("flipping twice gave %q, want %q", back.ToFEN(), b.ToFEN())
+	}
+	for depth := 1; depth <= 3; depth++ {
+		if got, want := myengine.Perft(&f, depth), myengine.Perft(b, depth); got != want {
+			t.Errorf("perft(%d) of flipped board %d, original %d", depth, got, want)
+		}
+	}
+}
+
+func TestFlipColorsEnPassant(t *testing.T) {
+	b, err := myengine.ParseFEN("rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := b.FlipColors()
+	if want := "rnbqkbnr/pppp1ppp/8/8/3PpP2/8/PPP1P1PP/RNBQKBNR b KQkq f3 0 3"; f.ToFEN() != want {
+		t.Errorf("flipped FEN %q, want %q", f.ToFEN(), want)
+	}
+	if got, want := myengine.Perft(&f, 2), myengine.Perft(b, 2); got != want {
+		t.Errorf("perft(2) of flipped board %d, original %d", got, want)
+	}
+}
+
+func TestMirror(t *testing.T) {
+	b, err := myengine.ParseFEN("8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := b.Mirror()
+	if want := "8/5p2/4p3/r5PK/k1p3R1/8/1P1P4/8 w - - 0 1"; m.ToFEN() != want {
+		t.Errorf("mirrored FEN %q, want %q", m.ToFEN(), want)
+	}
+	if !m.Validate() {
+		t.Errorf("mirrored board invariants invalid")
+	}
+	if back := m.Mirror(); back.ToFEN() != b.ToFEN() || back.Hash() != b.Hash() {
+		t.Errorf("mirroring twice gave %q, want %q", back.ToFEN(), b.ToFEN())
+	}
+	for depth := 1; depth <= 3; depth++ {
+		if got, want := myengine.Perft(&m, depth), myengine.Perft(b, depth); got != want {
+			t.Errorf("perft(%d) of mirrored board %d, original %d", depth, got, want)
+		}
+	}
+
+	// Castling rights have no mirror image and are dropped.
+	c, err := myengine.ParseFEN(myengine.FENStartPos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mc := c.Mirror(); mc.CastlingRights() != 0 {
+		t.Errorf("mirrored start position kept castling rights %v", mc.CastlingRights())
+	}
+}
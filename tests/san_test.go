@@ -0,0 +1,99 @@
+package goose_engine_mg_test
+
+import (
+	"testing"
+
+	myengine "chess-engine/goosemg"
+)
+
+var sanRoundTripFENs = []string{
+	myengine.FENStartPos,
+	"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+	"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+	"r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1",
+	"rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+	"k7/8/8/3pP3/8/8/8/7K w - d6 0 2",
+}
+
+func TestSANRoundTrip(t *testing.T) {
+	for _, fen := range sanRoundTripFENs {
+		b, err := myengine.ParseFEN(fen)
+		if err != nil {
+			t.Fatalf("ParseFEN(%q): %v", fen, err)
+		}
+		for _, m := range b.GenerateMoves() {
+			san := b.SAN(m)
+			got, err := b.ParseSAN(san)
+			if err != nil {
+				t.Fatalf("%s: ParseSAN(%q) for %s: %v", fen, san, m, err)
+			}
+			if got != m {
+				t.Fatalf("%s: ParseSAN(%q) = %s, want %s", fen, san, got, m)
+			}
+		}
+	}
+}
+
+func TestSANNotation(t *testing.T) {
+	cases := []struct {
+		fen  string
+		uci  string
+		want string
+	}{
+		{myengine.FENStartPos, "g1f3", "Nf3"},
+		{"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", "e1g1", "O-O"},
+		{"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", "e1c1", "O-O-O"},
+		{"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", "e5f7", "Nxf7"},
+		{"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", "d5e6", "dxe6"},
+		{"k7/8/8/3pP3/8/8/8/7K w - d6 0 2", "e5d6", "exd6"},
+		{"1n5k/P7/8/8/8/8/8/7K w - - 0 1", "a7b8q", "axb8=Q+"},
+		{"6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", "a1a8", "Ra8#"},
+		{"k7/8/8/8/8/8/4K3/R6R w - - 0 1", "a1d1", "Rad1"},
+		{"k7/8/8/N7/8/8/8/N3K3 w - - 0 1", "a1b3", "N1b3"},
+	}
+	for _, tc := range cases {
+		b, err := myengine.ParseFEN(tc.fen)
+		if err != nil {
+			t.Fatalf("ParseFEN(%q): %v", tc.fen, err)
+		}
+		var m myengine.Move
+		for _, mv := range b.GenerateMoves() {
+			if mv.String() == tc.uci {
+				m = mv
+			}
+		}
+		if m == 0 {
+			t.Fatalf("%s: move %s not generated", tc.fen, tc.uci)
+		}
+		if got := b.SAN(m); got != tc.want {
+			t.Errorf("%s: SAN(%s) = %q, want %q", tc.fen, tc.uci, got, tc.want)
+		}
+	}
+}
+
+func TestParseSANVariants(t *testing.T) {
+	b, err := myengine.ParseFEN("1n5k/P7/8/8/8/8/3N4/4K2R w K - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for san, want := range map[string]string{
+		"axb8Q":  "a7b8q",
+		"axb8=N": "a7b8n",
+		"Ndf3":   "d2f3",
+		"Nd2-f3": "d2f3",
+		"0-0":    "e1g1",
+		"Kf1!?":  "e1f1",
+	} {
+		m, err := b.ParseSAN(san)
+		if err != nil {
+			t.Errorf("ParseSAN(%q): %v", san, err)
+			continue
+		}
+		if m.String() != want {
+			t.Errorf("ParseSAN(%q) = %s, want %s", san, m, want)
+		}
+	}
+	if _, err := b.ParseSAN("Qd4"); err == nil {
+		t.Errorf("ParseSAN(\"Qd4\") succeeded without a queen on the board")
+	}
+}
@@ -44,6 +44,28 @@ func TestStalemate_Basic(t *testing.T) {
 	}
 }
 
+func TestHasLegalMoves_MatchesGenerator(t *testing.T) {
+	fens := []string{
+		// Only the king can't move; the pawn still can
+		"7k/5Q2/6K1/8/8/8/p7/8 b - - 0 1",
+		// The king's only step captures a defended rook
+		"7k/6R1/6K1/8/8/8/8/8 b - - 0 1",
+		// The rook is pinned, so only the king can move
+		"k7/1r6/2B5/8/8/8/8/1R2K3 b - - 0 1",
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+	}
+	for _, fen := range fens {
+		b, err := myengine.ParseFEN(fen)
+		if err != nil {
+			t.Fatalf("ParseFEN(%q) failed: %v", fen, err)
+		}
+		want := len(b.GenerateMoves()) > 0
+		if got := b.HasLegalMoves(); got != want {
+			t.Errorf("%s: HasLegalMoves = %v, generator finds moves: %v", fen, got, want)
+		}
+	}
+}
+
 // Mate-in-one: make the mating move and verify the updated board detects checkmate
 func TestMateInOne_MakeAndDetect(t *testing.T) {
 	// White to move: Qxg7# with bishop on c3 protecting g7, black king on h8
@@ -6,7 +6,7 @@ import (
 	"os"
 )
 
-const modelLayoutTag = "linear_v11_tiered_layout"
+const modelLayoutTag = "linear_v13_tiered_layout"
 
 type pstJSON struct {
 	MG [6][64]float64 `json:"mg"`
@@ -59,6 +59,10 @@ type modelJSON struct {
 	QueenMobilityMG  []float64 `json:"queen_mobility_mg,omitempty"`
 	QueenMobilityEG  []float64 `json:"queen_mobility_eg,omitempty"`
 	KingSafety       []float64 `json:"king_safety_table,omitempty"`
+	ThreatsMG        []float64 `json:"threats_mg,omitempty"`
+	ThreatsEG        []float64 `json:"threats_eg,omitempty"`
+	PassedTermsMG    []float64 `json:"passed_terms_mg,omitempty"`
+	PassedTermsEG    []float64 `json:"passed_terms_eg,omitempty"`
 	// Phase 1 scalars
 	BishopPairMG       *float64 `json:"bishop_pair_mg,omitempty"`
 	BishopPairEG       *float64 `json:"bishop_pair_eg,omitempty"`
@@ -135,6 +139,10 @@ func SaveModelJSON(path string, fe Featurizer, pst *PST) error {
 			payload.QueenMobilityMG = append(payload.QueenMobilityMG, le.QueenMobilityMG[:]...)
 			payload.QueenMobilityEG = append(payload.QueenMobilityEG, le.QueenMobilityEG[:]...)
 			payload.KingSafety = append(payload.KingSafety, le.KingSafety[:]...)
+			payload.ThreatsMG = append(payload.ThreatsMG, le.ThreatMG[:]...)
+			payload.ThreatsEG = append(payload.ThreatsEG, le.ThreatEG[:]...)
+			payload.PassedTermsMG = append(payload.PassedTermsMG, le.PassedTermMG[:]...)
+			payload.PassedTermsEG = append(payload.PassedTermsEG, le.PassedTermEG[:]...)
 			// Phase 1
 			payload.BishopPairMG = floatPtr(le.BishopPairMG)
 			payload.BishopPairEG = floatPtr(le.BishopPairEG)
@@ -261,6 +269,18 @@ func LoadModelJSON(path string, fe Featurizer, pst *PST) error {
 	if len(m.KingSafety) == 100 {
 		copy(le.KingSafety[:], m.KingSafety)
 	}
+	if len(m.ThreatsMG) == len(le.ThreatMG) {
+		copy(le.ThreatMG[:], m.ThreatsMG)
+	}
+	if len(m.ThreatsEG) == len(le.ThreatEG) {
+		copy(le.ThreatEG[:], m.ThreatsEG)
+	}
+	if len(m.PassedTermsMG) == len(le.PassedTermMG) {
+		copy(le.PassedTermMG[:], m.PassedTermsMG)
+	}
+	if len(m.PassedTermsEG) == len(le.PassedTermEG) {
+		copy(le.PassedTermEG[:], m.PassedTermsEG)
+	}
 	// Phase 1
 	if m.BishopPairMG != nil {
 		le.BishopPairMG = *m.BishopPairMG
@@ -28,8 +28,8 @@ type mobilityValues struct {
 
 // LinearEval implements a linear tunable evaluation with MG/EG tapering.
 // Parameters are organized into 4 tiers for toggle control:
-//   - Tier 1: Core (PST, Material, Mobility, Outposts, RookFiles, StackedRooks, MobCenter)
-//   - Tier 2: Pawns (Passers, PawnStruct)
+//   - Tier 1: Core (PST, Material, Mobility, Outposts, RookFiles, StackedRooks, MobCenter, Threats)
+//   - Tier 2: Pawns (Passers, PawnStruct, PassedTerms)
 //   - Tier 3: King Safety (Table, Corr, Endgame, Tropism, PawnStorm, WeakKingSquares)
 //   - Tier 4: Misc (BishopPair, Imbalance, Space, Tempo)
 type LinearEval struct {
@@ -69,6 +69,10 @@ type LinearEval struct {
 	CandidatePassedPctMG float64
 	CandidatePassedPctEG float64
 
+	// Tier 2: Dynamic passed pawn terms, indexed by engine term (eng.PassedFreePath, ...)
+	PassedTermMG [eng.PassedTermCount]float64
+	PassedTermEG [eng.PassedTermCount]float64
+
 	// Tier 1: Mobility tables (per piece, MG/EG)
 	KnightMobilityMG [9]float64
 	KnightMobilityEG [9]float64
@@ -114,6 +118,10 @@ type LinearEval struct {
 	KnightMobCenterMG float64
 	BishopMobCenterMG float64
 
+	// Tier 1: Threats, indexed by engine threat kind (eng.ThreatByPawn, ...)
+	ThreatMG [eng.ThreatCount]float64
+	ThreatEG [eng.ThreatCount]float64
+
 	// Tier 4: Material imbalance scalars
 	ImbalanceKnightPerPawnMG float64
 	ImbalanceKnightPerPawnEG float64
@@ -131,6 +139,8 @@ type LinearEval struct {
 		ksSemiOpen, ksOpen, ksMinorDef, ksPawnDef int
 		exMG                                      [3]int
 		exEG                                      [2]int
+		threats                                   [eng.ThreatCount]int
+		passedTerms                               [eng.PassedTermCount]int
 		imbMG                                     [2]int
 		imbEG                                     [2]int
 	}
@@ -297,6 +307,24 @@ func (le *LinearEval) Eval(pos *Position) float64 {
 			bc = 1.0
 		}
 		eg += (wc - bc) * le.QueenCentralizedEG
+
+		// Threats (white minus black counts per kind)
+		threats := eng.ThreatDiffs((*gm.Board)(pos))
+		le.cache.threats = threats
+		thMG, thEG := 0.0, 0.0
+		for i, n := range threats {
+			thMG += float64(n) * le.ThreatMG[i]
+			thEG += float64(n) * le.ThreatEG[i]
+		}
+		mg += thMG
+		eg += thEG
+		if le.Debug {
+			println("################### THREATS EVALUATION ###################")
+			println("Threat counts: pawn=", threats[eng.ThreatByPawn], " minor=", threats[eng.ThreatByMinor],
+				" rook=", threats[eng.ThreatByRook], " hanging=", threats[eng.ThreatHanging],
+				" push=", threats[eng.ThreatByPawnPush], " restricted=", threats[eng.ThreatRestricted])
+			println("Threats: MG=", thMG, " EG=", thEG)
+		}
 	}
 
 	// ===== TIER 2: Pawn Structure (Passers + PawnStruct) =====
@@ -315,7 +343,7 @@ func (le *LinearEval) Eval(pos *Position) float64 {
 			eg -= le.PasserEG[rev]
 		}
 		// Candidate passed pawns (lever/capture potential)
-		pawnEntry := eng.GetPawnEntry((*gm.Board)(pos), false)
+		pawnEntry := eng.GetPawnEntry((*gm.Board)(pos))
 		candMG, candEG := candidatePasserBonus(pos, pawnEntry, wPassed, bPassed, le.PasserMG, le.PasserEG, le.CandidatePassedPctMG, le.CandidatePassedPctEG)
 		mg += candMG
 		eg += candEG
@@ -336,10 +364,17 @@ func (le *LinearEval) Eval(pos *Position) float64 {
 		eg += float64(egDiffs[6]) * le.WeakLeverEG
 		mg += float64(mgDiffs[7]) * le.BackwardMG
 		eg += float64(egDiffs[7]) * le.BackwardEG
+		// Dynamic passed pawn terms (white minus black counts per term)
+		passedTerms := eng.PassedPawnDiffs((*gm.Board)(pos))
+		for i, n := range passedTerms {
+			mg += float64(n) * le.PassedTermMG[i]
+			eg += float64(n) * le.PassedTermEG[i]
+		}
 		// cache pawn diffs
 		le.cache.pos = (*gm.Board)(pos)
 		le.cache.pawnMG = mgDiffs
 		le.cache.pawnEG = egDiffs
+		le.cache.passedTerms = passedTerms
 		if le.Debug {
 			println("################### PAWN STRUCTURE EVALUATION ###################")
 			println("Feature differences (white - black):")
@@ -562,7 +597,7 @@ func (le *LinearEval) Grad(pos *Position, scale float64, g []float64) {
 			g[passMGBase+rev] -= scale * mgf * scalePass
 			g[passEGBase+rev] -= scale * egf * scalePass
 		}
-		pawnEntry := eng.GetPawnEntry((*gm.Board)(pos), false)
+		pawnEntry := eng.GetPawnEntry((*gm.Board)(pos))
 		candMGIdx := le.layout.PawnStructStart + 14
 		candEGIdx := le.layout.PawnStructStart + 15
 		candidatePasserGrad(pos, pawnEntry, wPassed, bPassed, le.PasserMG, le.PasserEG, passMGBase, passEGBase, candMGIdx, candEGIdx, g, scale, mgf, egf, le.CandidatePassedPctMG, le.CandidatePassedPctEG)
@@ -668,6 +703,25 @@ func (le *LinearEval) Grad(pos *Position, scale float64, g []float64) {
 		g[pawnStructBase+13] += scale * egf * float64(egDiffs[7])
 	}
 
+	// Dynamic passed pawn gradients (Tier2)
+	if le.Toggles.Tier2Train {
+		var passedTerms [eng.PassedTermCount]int
+		if le.cache.pos == (*gm.Board)(pos) {
+			passedTerms = le.cache.passedTerms
+		} else {
+			passedTerms = eng.PassedPawnDiffs((*gm.Board)(pos))
+		}
+		passedMGBase := le.layout.PassedTermsStart
+		passedEGBase := passedMGBase + eng.PassedTermCount
+		for i, n := range passedTerms {
+			if !le.Toggles.ParamTrain.PassedTerms[i] {
+				continue
+			}
+			g[passedMGBase+i] += scale * mgf * float64(n)
+			g[passedEGBase+i] += scale * egf * float64(n)
+		}
+	}
+
 	// Mobility gradients (Tier1)
 	var mobCounts mobilityCounts
 	var mobValues mobilityValues
@@ -721,6 +775,25 @@ func (le *LinearEval) Grad(pos *Position, scale float64, g []float64) {
 		}
 	}
 
+	// Threat gradients (Tier1)
+	if le.Toggles.Tier1Train {
+		var threats [eng.ThreatCount]int
+		if le.cache.pos == (*gm.Board)(pos) {
+			threats = le.cache.threats
+		} else {
+			threats = eng.ThreatDiffs((*gm.Board)(pos))
+		}
+		threatMGBase := le.layout.ThreatsStart
+		threatEGBase := threatMGBase + eng.ThreatCount
+		for i, n := range threats {
+			if !le.Toggles.ParamTrain.Threats[i] {
+				continue
+			}
+			g[threatMGBase+i] += scale * mgf * float64(n)
+			g[threatEGBase+i] += scale * egf * float64(n)
+		}
+	}
+
 	// ===== TIER 3: King Safety (Table, Corr, Endgame, Tropism, PawnStorm, WeakKingSquares) =====
 	// King safety table (MG + EG with /4 factor)
 	kingTableBase := le.layout.KingTableStart
@@ -1086,7 +1159,7 @@ func candidatePasserBonus(pos *Position, entry *eng.PawnHashEntry, wPassed, bPas
 	if entry == nil {
 		return 0, 0
 	}
-	occ := pos.White.All | pos.Black.All
+	occ := pos.White.Pawns | pos.Black.Pawns
 	pctMG := candPctMG / 100.0
 	pctEG := candPctEG / 100.0
 
@@ -1172,7 +1245,7 @@ func candidatePasserGrad(pos *Position, entry *eng.PawnHashEntry, wPassed, bPass
 	if entry == nil {
 		return
 	}
-	occ := pos.White.All | pos.Black.All
+	occ := pos.White.Pawns | pos.Black.Pawns
 	pctMG := candPctMG / 100.0
 	pctEG := candPctEG / 100.0
 
@@ -2,8 +2,8 @@ package tuner
 
 // Params returns the flattened parameter vector θ using the consolidated layout.
 // Layout order (see phase_offsets.go for offsets):
-//   - Tier 1: PST MG/EG, Material MG/EG, Mobility MG/EG, Core scalars, Tier1 extras
-//   - Tier 2: Passers MG/EG, PawnStruct
+//   - Tier 1: PST MG/EG, Material MG/EG, Mobility MG/EG, Core scalars, Tier1 extras, Threats
+//   - Tier 2: Passers MG/EG, PawnStruct, PassedTerms
 //   - Tier 3: King table, correlates, endgame, Tier3 extras, WeakKingSquares
 //   - Tier 4: BishopPair, Imbalance, Space/Tempo
 func (le *LinearEval) Params() []float64 {
@@ -21,8 +21,10 @@ func (le *LinearEval) Params() []float64 {
 		off = le.writeMobilityToTheta(off)
 		off = le.writeCoreScalarsToTheta(off)
 		off = le.writeTier1ExtrasToTheta(off)
+		off = le.writeThreatsToTheta(off)
 		off = le.writePassersToTheta(off)
 		off = le.writePawnStructToTheta(off)
+		off = le.writePassedTermsToTheta(off)
 		off = le.writeKingTableToTheta(off)
 		off = le.writeKingCorrToTheta(off)
 		off = le.writeKingEndgameToTheta(off)
@@ -61,8 +63,10 @@ func (le *LinearEval) SetParams(p []float64) {
 		off = le.readMobilityFromTheta(off)
 		off = le.readCoreScalarsFromTheta(off)
 		off = le.readTier1ExtrasFromTheta(off)
+		off = le.readThreatsFromTheta(off)
 		off = le.readPassersFromTheta(off)
 		off = le.readPawnStructFromTheta(off)
+		off = le.readPassedTermsFromTheta(off)
 		off = le.readKingTableFromTheta(off)
 		off = le.readKingCorrFromTheta(off)
 		off = le.readKingEndgameFromTheta(off)
@@ -1,5 +1,7 @@
 package tuner
 
+import eng "chess-engine/engine"
+
 // DefaultLRScales returns the recommended per-parameter learning rate multipliers.
 func DefaultLRScales() LRScaleConfig {
 	return LRScaleConfig{
@@ -34,6 +36,8 @@ func DefaultLRScales() LRScaleConfig {
 		Material:              0.5,
 
 		// Tier 3: Light constraint
+		Threats:       0.5,
+		PassedTerms:   0.5,
 		ConnectedPawn: 0.7,
 		BlockedPawn:   0.7,
 		BadBishop:     0.7,
@@ -110,6 +114,11 @@ func BuildLRScaleVector(layout Layout, cfg LRScaleConfig) []float64 {
 	scales[ps+14] = cfg.CandidatePassed // CandidatePassedPctMG
 	scales[ps+15] = cfg.CandidatePassed // CandidatePassedPctEG
 
+	// Dynamic passed pawn terms (MG then EG)
+	for i := layout.PassedTermsStart; i < layout.PassedTermsStart+2*eng.PassedTermCount; i++ {
+		scales[i] = cfg.PassedTerms
+	}
+
 	// Phase 3: Mobility (MG/EG)
 	mobilityCount := 9 + 14 + 15 + 22
 	for i := layout.MobilityMGStart; i < layout.MobilityMGStart+mobilityCount; i++ {
@@ -145,6 +154,11 @@ func BuildLRScaleVector(layout Layout, cfg LRScaleConfig) []float64 {
 	scales[ex1+7] = cfg.BadBishop // BadBishopMG
 	scales[ex1+8] = cfg.BadBishop // BadBishopEG
 
+	// Threats (MG then EG)
+	for i := layout.ThreatsStart; i < layout.ThreatsStart+2*eng.ThreatCount; i++ {
+		scales[i] = cfg.Threats
+	}
+
 	// Tier3 extras
 	ex3 := layout.Tier3ExtrasStart
 	scales[ex3+0] = cfg.KnightTropism // KnightTropismMG
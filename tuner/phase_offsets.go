@@ -1,11 +1,13 @@
 package tuner
 
+import eng "chess-engine/engine"
+
 // Layout consolidates theta layout offsets for easier maintenance.
 // Keep this consistent with exporter and SetParams/Params helpers.
 //
 // Strict tier block order (theta):
-//   - Tier 1: PST MG/EG, Material MG/EG, Mobility MG/EG, Core scalars, Tier1 extras
-//   - Tier 2: Passers MG/EG, PawnStruct
+//   - Tier 1: PST MG/EG, Material MG/EG, Mobility MG/EG, Core scalars, Tier1 extras, Threats
+//   - Tier 2: Passers MG/EG, PawnStruct, PassedTerms
 //   - Tier 3: King table, King correlates, King endgame, Tier3 extras, WeakKing
 //   - Tier 4: BishopPair, Imbalance, Space/Tempo
 type Layout struct {
@@ -15,10 +17,12 @@ type Layout struct {
 	MobilityMGStart, MobilityEGStart int // 60, 60
 	CoreScalarStart                  int // 4 (Rook files, SeventhRank, QueenCentralized)
 	Tier1ExtrasStart                 int // 9 (Outposts, StackedRooks, MobCenter, BadBishop)
+	ThreatsStart                     int // 12 (6 threat kinds MG, then EG)
 
 	// Tier 2
 	PasserMGStart, PasserEGStart int // 64, 64
 	PawnStructStart              int // 16
+	PassedTermsStart             int // 12 (6 dynamic passer terms MG, then EG)
 
 	// Tier 3
 	KingTableStart   int // 100
@@ -32,7 +36,7 @@ type Layout struct {
 	ImbalanceStart  int // 4
 	SpaceTempoStart int // 3 (SpaceMG, SpaceEG, Tempo)
 
-	Total int // 1217
+	Total int // 1241
 }
 
 func computeLayout() Layout {
@@ -54,12 +58,16 @@ func computeLayout() Layout {
 	off += 4
 	l.Tier1ExtrasStart = off
 	off += 9
+	l.ThreatsStart = off
+	off += 2 * eng.ThreatCount
 	l.PasserMGStart = off
 	off += 64
 	l.PasserEGStart = off
 	off += 64
 	l.PawnStructStart = off
 	off += 16
+	l.PassedTermsStart = off
+	off += 2 * eng.PassedTermCount
 	l.KingTableStart = off
 	off += 100
 	l.KingCorrStart = off
@@ -5,10 +5,11 @@ package tuner
 //
 // Tier mapping for each block (see toggles.go for tier definitions):
 //   - PST, Material, Mobility: Tier 1 (Core)
-//   - Passers, PawnStruct: Tier 2 (Pawns)
+//   - Passers, PawnStruct, PassedTerms: Tier 2 (Pawns)
 //   - P1 Scalars: Tier 1 (RookFiles, SeventhRank, QueenCentralized) + Tier 4 (BishopPair)
 //   - KingTable, KingCorr, KingEndgame: Tier 3 (King Safety)
 //   - Extras: Tier 1 (Outposts, StackedRooks, MobCenter) + Tier 3 (Tropism, PawnStorm)
+//   - Threats: Tier 1 (Core)
 //   - Imbalance: Tier 4 (Misc)
 //   - WeakTempo: Tier 3 (WeakKingSquares) + Tier 4 (Space, Tempo)
 
@@ -166,6 +167,30 @@ func (le *LinearEval) writeTier1ExtrasToTheta(off int) int {
 	return off + 9
 }
 
+func (le *LinearEval) writePassedTermsToTheta(off int) int {
+	for i := 0; i < len(le.PassedTermMG); i++ {
+		le.theta[off+i] = le.PassedTermMG[i]
+	}
+	off += len(le.PassedTermMG)
+	for i := 0; i < len(le.PassedTermEG); i++ {
+		le.theta[off+i] = le.PassedTermEG[i]
+	}
+	off += len(le.PassedTermEG)
+	return off
+}
+
+func (le *LinearEval) writeThreatsToTheta(off int) int {
+	for i := 0; i < len(le.ThreatMG); i++ {
+		le.theta[off+i] = le.ThreatMG[i]
+	}
+	off += len(le.ThreatMG)
+	for i := 0; i < len(le.ThreatEG); i++ {
+		le.theta[off+i] = le.ThreatEG[i]
+	}
+	off += len(le.ThreatEG)
+	return off
+}
+
 func (le *LinearEval) writeTier3ExtrasToTheta(off int) int {
 	le.theta[off+0] = le.KnightTropismMG
 	le.theta[off+1] = le.KnightTropismEG
@@ -339,6 +364,30 @@ func (le *LinearEval) readTier1ExtrasFromTheta(off int) int {
 	return off + 9
 }
 
+func (le *LinearEval) readPassedTermsFromTheta(off int) int {
+	for i := 0; i < len(le.PassedTermMG); i++ {
+		le.PassedTermMG[i] = le.theta[off+i]
+	}
+	off += len(le.PassedTermMG)
+	for i := 0; i < len(le.PassedTermEG); i++ {
+		le.PassedTermEG[i] = le.theta[off+i]
+	}
+	off += len(le.PassedTermEG)
+	return off
+}
+
+func (le *LinearEval) readThreatsFromTheta(off int) int {
+	for i := 0; i < len(le.ThreatMG); i++ {
+		le.ThreatMG[i] = le.theta[off+i]
+	}
+	off += len(le.ThreatMG)
+	for i := 0; i < len(le.ThreatEG); i++ {
+		le.ThreatEG[i] = le.theta[off+i]
+	}
+	off += len(le.ThreatEG)
+	return off
+}
+
 func (le *LinearEval) readTier3ExtrasFromTheta(off int) int {
 	le.KnightTropismMG = le.theta[off+0]
 	le.KnightTropismEG = le.theta[off+1]
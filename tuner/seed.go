@@ -94,6 +94,14 @@ func SeedFromEngineDefaults(le *LinearEval, pst *PST) {
 	}
 	le.KnightMobCenterMG = 0.01
 	le.BishopMobCenterMG = 0.01
+	for i := 0; i < eng.PassedTermCount; i++ {
+		le.PassedTermMG[i] = float64(eng.PassedTermMG[i])
+		le.PassedTermEG[i] = float64(eng.PassedTermEG[i])
+	}
+	for i := 0; i < eng.ThreatCount; i++ {
+		le.ThreatMG[i] = float64(eng.ThreatMG[i])
+		le.ThreatEG[i] = float64(eng.ThreatEG[i])
+	}
 
 	// Phase 4: King safety table
 	ks := eng.DefaultKingSafetyTable()
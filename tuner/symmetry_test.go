@@ -0,0 +1,150 @@
+package tuner
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	gm "chess-engine/goosemg"
+)
+
+var symmetryFens = []string{
+	gm.Startpos,
+	"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+	"r2q1rk1/pp1nbppp/2p1bn2/3p2B1/3P4/2N1PN2/PPQ2PPP/R3KB1R w KQ - 2 10",
+	"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+	"4k3/8/4K3/4P3/8/8/8/8 b - - 0 1",
+}
+
+// symmetryPositions plays seeded random games from symmetryFens and returns
+// up to n of the positions reached.
+func symmetryPositions(n int) []gm.Board {
+	rng := rand.New(rand.NewSource(49))
+	positions := make([]gm.Board, 0, n)
+	for len(positions) < n {
+		for _, fen := range symmetryFens {
+			b := gm.ParseFen(fen)
+			for ply := 0; ply < 80 && len(positions) < n; ply++ {
+				moves := b.GenerateMoves()
+				if len(moves) == 0 {
+					break
+				}
+				b.Apply(moves[rng.Intn(len(moves))])
+				positions = append(positions, b)
+			}
+		}
+	}
+	return positions
+}
+
+type thetaBlock struct {
+	name       string
+	start, end int
+}
+
+// layoutBlocks splits theta into the named blocks of the layout.
+func layoutBlocks(l Layout) []thetaBlock {
+	var blocks []thetaBlock
+	v := reflect.ValueOf(l)
+	for i := 0; i < v.NumField(); i++ {
+		if name := v.Type().Field(i).Name; strings.HasSuffix(name, "Start") {
+			blocks = append(blocks, thetaBlock{name: strings.TrimSuffix(name, "Start"), start: int(v.Field(i).Int())})
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].start < blocks[j].start })
+	for i := range blocks {
+		blocks[i].end = l.Total
+		if i+1 < len(blocks) {
+			blocks[i].end = blocks[i+1].start
+		}
+	}
+	return blocks
+}
+
+// gradDiff names the theta blocks whose summed gradient breaks the symmetry
+// between b and its transform c. The gradient is the feature vector of the
+// linear terms, so a block sum only moves when that term family does.
+func gradDiff(le *LinearEval, b, c *gm.Board, sign float64) string {
+	grad := func(pos *gm.Board) []float64 {
+		g := make([]float64, le.layout.Total)
+		le.Eval(pos)
+		le.Grad(pos, 1, g)
+		return g
+	}
+	want, got := grad(b), grad(c)
+	var diffs []string
+	for _, blk := range layoutBlocks(le.layout) {
+		var sumWant, sumGot float64
+		for i := blk.start; i < blk.end; i++ {
+			sumWant += want[i]
+			sumGot += got[i]
+		}
+		if math.Abs(sumGot*sign-sumWant) > 1e-6 {
+			diffs = append(diffs, fmt.Sprintf("%s: %.4f vs %.4f", blk.name, sumWant, sumGot))
+		}
+	}
+	return strings.Join(diffs, "; ")
+}
+
+func newSymmetryEval() *LinearEval {
+	pst := &PST{}
+	le := &LinearEval{PST: pst}
+	SeedFromEngineDefaults(le, pst)
+	le.Toggles = DefaultEvalToggles()
+	le.ensureLayout()
+	return le
+}
+
+func checkLinearSymmetry(t *testing.T, le *LinearEval, kind string, b, c *gm.Board, sign float64) {
+	t.Helper()
+	want := le.Eval(b)
+	if got := le.Eval(c); math.Abs(got*sign-want) > 1e-6 {
+		t.Errorf("%s of %s: eval %.4f, original %.4f; blocks: %s", kind, b.ToFen(), got, want, gradDiff(le, b, c, sign))
+	}
+}
+
+func TestLinearEvalColorSymmetry(t *testing.T) {
+	le := newSymmetryEval()
+	for _, b := range symmetryPositions(2000) {
+		c := b.FlipColors()
+		checkLinearSymmetry(t, le, "color flip", &b, &c, -1)
+		if t.Failed() {
+			return
+		}
+	}
+}
+
+func TestLinearEvalMirrorSymmetry(t *testing.T) {
+	le := newSymmetryEval()
+	// The seeded square tables are lopsided by design; make them left-right
+	// symmetric so the rest of the evaluation is what gets tested.
+	average := func(table *[64]float64) {
+		for sq := 0; sq < 64; sq++ {
+			if sq&7 < 4 {
+				avg := (table[sq] + table[sq^7]) / 2
+				table[sq], table[sq^7] = avg, avg
+			}
+		}
+	}
+	for pt := range le.PST.MG {
+		average(&le.PST.MG[pt])
+		average(&le.PST.EG[pt])
+	}
+	average(&le.PasserMG)
+	average(&le.PasserEG)
+
+	for _, b := range symmetryPositions(2000) {
+		if b.CastlingRights() != 0 {
+			continue
+		}
+		c := b.Mirror()
+		checkLinearSymmetry(t, le, "mirror", &b, &c, 1)
+		if t.Failed() {
+			return
+		}
+	}
+}
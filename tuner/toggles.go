@@ -1,13 +1,15 @@
 package tuner
 
+import eng "chess-engine/engine"
+
 // PhaseToggles control which tiers run for evaluation and training.
 // Turn Eval=true to include a tier in scoring; turn Train=true to update its weights.
 // Eval=true + Train=false = freeze tier; both=false = exclude tier entirely.
 //
 // Tier structure:
 //
-//	Tier 1 — Core: PST, Material, Mobility, Outposts, RookFiles, StackedRooks, MobCenter scaling
-//	Tier 2 — Pawns: Passers, PawnStruct (doubled/isolated/connected/phalanx/blocked/weak lever/backward)
+//	Tier 1 — Core: PST, Material, Mobility, Outposts, RookFiles, StackedRooks, MobCenter scaling, Threats
+//	Tier 2 — Pawns: Passers, PawnStruct (doubled/isolated/connected/phalanx/blocked/weak lever/backward), PassedTerms
 //	Tier 3 — King Safety: KingTable, KingCorr, KingEndgame, Tropism, PawnStorm, WeakKingSquares
 //	Tier 4 — Misc: BishopPair, Imbalance, Space, Tempo
 //
@@ -57,6 +59,9 @@ type ParamTrainToggles struct {
 	CandidatePassedMG bool
 	CandidatePassedEG bool
 
+	// Dynamic passed pawn terms (6, MG+EG share a switch), indexed by eng.PassedFreePath, ...
+	PassedTerms [eng.PassedTermCount]bool
+
 	// Stage 4: Mobility per piece (index by gm piece: 0..6), covers table entries per piece.
 	MobilityMG [7]bool
 	MobilityEG [7]bool
@@ -95,6 +100,9 @@ type ParamTrainToggles struct {
 	ExtraKnightMobCenterMG bool
 	ExtraBishopMobCenterMG bool
 
+	// Threats (6 kinds, MG+EG share a switch), indexed by eng.ThreatByPawn, ...
+	Threats [eng.ThreatCount]bool
+
 	// Stage 5: King safety table (100) - single switch; correlates (4) individually.
 	KingTable bool
 	KingCorr  [4]bool
@@ -144,6 +152,9 @@ func DefaultTrainToggles() ParamTrainToggles {
 	for i := 0; i < len(t.PawnStruct); i++ {
 		t.PawnStruct[i] = true
 	}
+	for i := 0; i < len(t.PassedTerms); i++ {
+		t.PassedTerms[i] = true
+	}
 	for i := 0; i < len(t.MobilityMG); i++ {
 		t.MobilityMG[i] = true
 	}
@@ -157,6 +168,9 @@ func DefaultTrainToggles() ParamTrainToggles {
 	for i := 0; i < len(t.Extras); i++ {
 		t.Extras[i] = true
 	}
+	for i := 0; i < len(t.Threats); i++ {
+		t.Threats[i] = true
+	}
 	for i := 0; i < len(t.Imbalance); i++ {
 		t.Imbalance[i] = true
 	}
@@ -100,6 +100,8 @@ type LRScaleConfig struct {
 	PawnStormOppositeMult float64
 
 	// Tier 3: Light constraint (0.5x LR)
+	Threats       float64
+	PassedTerms   float64
 	ConnectedPawn float64
 	BlockedPawn   float64
 	BadBishop     float64
@@ -27,80 +27,139 @@ var benchPositions = []string{
 
 const benchDepth = 11
 
-// runBench runs a benchmark search on standard positions and reports total nodes
-func runBench() {
-	totalNodes := 0
-	var totalTimeSpent int64 = 0
+// benchHashMB is the TT size every bench runs with, so the node count
+// doesn't depend on the Hash option.
+const benchHashMB = 16
+
+// benchSignature is the node count of each bench position at benchDepth and
+// benchHashMB. Changes that aren't meant to alter the search must leave it
+// untouched; "bench verify" and TestBenchSignature check it.
+var benchSignature = []int{
+	498861, 277640, 88297, 102756, 127149,
+	139942, 111875, 140998, 95097, 81656,
+}
 
+// benchResult is the outcome of searching one bench position.
+type benchResult struct {
+	fen      string
+	nodes    int
+	bestMove string
+}
+
+// benchSearch searches every bench position to depth from a clean state.
+// With softNodes > 0 a search starts no new iteration once that many nodes
+// are spent, and with hardNodes > 0 it stops outright after that many; the
+// clock is never consulted, so the results are reproducible.
+func benchSearch(depth int, softNodes, hardNodes int) (results []benchResult, timeSpent int64) {
+	if prevSize := engine.TTSize; prevSize != benchHashMB {
+		engine.SearchState.ResizeHash(benchHashMB)
+		defer engine.SearchState.ResizeHash(prevSize)
+	}
 	for _, fen := range benchPositions {
 		board := gm.ParseFen(fen)
 		engine.SearchState.ResetForNewGame()
+		engine.SearchState.SetNodeLimits(softNodes, hardNodes)
 
 		// Search with fixed depth, large time, no time-based cutoff
-		engine.StartSearch(&board, uint8(benchDepth), 1000000, 0, 0, true, false, false, false)
+		bestMove := engine.StartSearch(&board, uint8(depth), 1000000, 0, 0, true, false, false, false)
 
-		// Accumulate nodes
-		totalNodes += engine.GetNodeCount()
-		totalTimeSpent += engine.GetTimeSpent()
+		results = append(results, benchResult{fen: fen, nodes: engine.GetNodeCount(), bestMove: bestMove})
+		timeSpent += engine.GetTimeSpent()
 	}
-
-	nps := uint64(float64(totalNodes*1000) / float64(totalTimeSpent))
-
-	fmt.Printf("%d nodes %d nps\n", totalNodes, nps)
+	return results, timeSpent
 }
 
-// parseIntOption parses an integer value from "setoption name X value Y" commands
-func parseIntOption(scanner *bufio.Scanner, optionName string) (int, bool) {
-	if !scanner.Scan() {
-		fmt.Printf("info string Malformed setoption for %s\n", optionName)
-		return 0, false
+// benchSignatureDiffs compares bench results at benchDepth with
+// benchSignature and describes every position whose node count changed.
+func benchSignatureDiffs(results []benchResult) []string {
+	if len(results) != len(benchSignature) {
+		return []string{fmt.Sprintf("%d bench positions but %d signature entries", len(results), len(benchSignature))}
 	}
-	scanner.Scan()
-	val, err := strconv.Atoi(scanner.Text())
-	if err != nil {
-		fmt.Printf("info string Malformed value for %s: %v\n", optionName, err)
-		return 0, false
+	var diffs []string
+	total, want := 0, 0
+	for i, r := range results {
+		total += r.nodes
+		want += benchSignature[i]
+		if r.nodes != benchSignature[i] {
+			diffs = append(diffs, fmt.Sprintf("position %d: %d nodes, signature %d (%+d)  %s",
+				i+1, r.nodes, benchSignature[i], r.nodes-benchSignature[i], r.fen))
+		}
 	}
-	return val, true
+	if len(diffs) > 0 {
+		diffs = append(diffs, fmt.Sprintf("total: %d nodes, signature %d (%+d)", total, want, total-want))
+	}
+	return diffs
 }
 
-// UCI options with bounds and setter
-type uciOption struct {
-	min, max int
-	setter   func(int)
+// verifyBench runs the bench at benchDepth and checks it against
+// benchSignature, printing the per-position differences on a mismatch.
+func verifyBench() bool {
+	results, _ := benchSearch(benchDepth, 0, 0)
+	diffs := benchSignatureDiffs(results)
+	if len(diffs) == 0 {
+		total := 0
+		for _, r := range results {
+			total += r.nodes
+		}
+		fmt.Printf("bench signature OK: %d nodes\n", total)
+		return true
+	}
+	fmt.Println("bench signature MISMATCH")
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+	return false
 }
 
-var uciThreads = 1
-
-var uciOptionSetters = map[string]uciOption{
-	"hash":    {1, 4096, func(v int) { engine.TTSize = v }},
-	"threads": {1, 1, func(v int) { uciThreads = v }},
-
-	"futilitybase":  {10, 30, func(v int) { engine.FutilityBase = int32(v) }},
-	"futilityscale": {50, 150, func(v int) { engine.FutilityScale = int32(v) }},
-
-	"rfpscale":      {50, 150, func(v int) { engine.RFPScale = int32(v) }},
-	"razoringscale": {100, 200, func(v int) { engine.RazoringScale = int32(v) }},
+// runBench runs a benchmark search on standard positions and reports total nodes.
+// Arguments are optional "depth N", "nodes N" (hard node limit) and
+// "softnodes N" pairs, or "verify" to check the node counts against
+// benchSignature. It reports false only when a verification fails.
+func runBench(args []string) bool {
+	if len(args) > 0 && strings.EqualFold(args[0], "verify") {
+		return verifyBench()
+	}
+	depth := benchDepth
+	softNodes, hardNodes := 0, 0
+	for i := 0; i+1 < len(args); i += 2 {
+		v, err := strconv.Atoi(args[i+1])
+		if err != nil || v <= 0 {
+			fmt.Println("info string Malformed bench option", args[i], args[i+1])
+			return true
+		}
+		switch strings.ToLower(args[i]) {
+		case "depth":
+			depth = v
+		case "nodes":
+			hardNodes = v
+		case "softnodes":
+			softNodes = v
+		default:
+			fmt.Println("info string Unknown bench option", args[i])
+			return true
+		}
+	}
 
-	"lmpoffset":       {1, 6, func(v int) { engine.LMPOffset = v }},
-	"lmrdepthlimit":   {2, 20, func(v int) { engine.LMRDepthLimit = int8(v) }},
-	"lmrmovelimit":    {2, 8, func(v int) { engine.LMRMoveLimit = v }},
-	"lmrhistorybonus": {450, 550, func(v int) { engine.LMRHistoryBonus = v }},
-	"lmrhistorymalus": {-150, -50, func(v int) { engine.LMRHistoryMalus = v }},
+	results, totalTimeSpent := benchSearch(depth, softNodes, hardNodes)
+	totalNodes := 0
+	for _, r := range results {
+		totalNodes += r.nodes
+	}
+	if totalTimeSpent == 0 {
+		totalTimeSpent = 1
+	}
 
-	"nullmovemindepth":    {0, 10, func(v int) { engine.NullMoveMinDepth = int8(v) }},
-	"nmmarginbase":        {120, 250, func(v int) { engine.NullMoveMinDepth = int8(v) }},
-	"nmmargindepth":       {10, 25, func(v int) { engine.NullMoveMinDepth = int8(v) }},
-	"quiescenceseemargin": {100, 200, func(v int) { engine.QuiescenceSeeMargin = v }},
-	"probcutseemargin":    {100, 200, func(v int) { engine.ProbCutSeeMargin = v }},
+	nps := uint64(float64(totalNodes*1000) / float64(totalTimeSpent))
 
-	"deltamargin":          {100, 300, func(v int) { engine.DeltaMargin = int32(v) }},
-	"aspirationwindowsize": {10, 100, func(v int) { engine.AspirationWindowSize = int32(v) }},
+	fmt.Printf("%d nodes %d nps\n", totalNodes, nps)
+	return true
 }
 
 func main() {
 	if len(os.Args) >= 2 && os.Args[1] == "bench" {
-		runBench()
+		if !runBench(os.Args[2:]) {
+			os.Exit(1)
+		}
 		os.Exit(0)
 	}
 	uciLoop()
@@ -110,7 +169,6 @@ func uciLoop() {
 	scanner := bufio.NewScanner(os.Stdin)
 	board := gm.ParseFen(gm.Startpos) // the game board
 
-	var evalOnly = false
 	var moveOrderingOnly = false
 	var printSearchInformation = true
 
@@ -122,57 +180,27 @@ func uciLoop() {
 		}
 		switch strings.ToLower(tokens[0]) {
 		case "bench":
-			runBench()
+			runBench(tokens[1:])
 		case "eval":
-			evalOnly = true
+			trace := engine.EvaluateWithTrace(&board)
+			if len(tokens) > 1 && strings.ToLower(tokens[1]) == "json" {
+				trace.WriteJSON(os.Stdout)
+			} else {
+				trace.WriteTable(os.Stdout)
+			}
 		case "HideSearchInfo":
 			printSearchInformation = !printSearchInformation
 		case "moveordering":
 			moveOrderingOnly = true
 		case "cutstats":
 			engine.PrintCutStats = true
+		case "verifypsqt":
+			gm.VerifyPSQT = true
 		case "uci":
 			fmt.Println("id name GooseEngine Alpha version 0.2")
 			fmt.Println("id author Goose")
 
-			fmt.Printf("option name Hash type spin default %d min 1 max 4096\n", engine.TTSize)
-			fmt.Printf("option name Threads type spin default %d min 1 max 1\n", uciThreads)
-
-			// --- Search / pruning parameters exposed as UCI options ---
-
-			// Futility margins (node-level) - base ±50
-			fmt.Printf("option name FutilityBase type spin default %d min 10 max 30\n", engine.FutilityBase)
-			fmt.Printf("option name FutilityScale type spin default %d min 50 max 150\n", engine.FutilityScale)
-
-			// Reverse Futility Pruning (Static Null Move) margins - base ±50
-			fmt.Printf("option name RFPScale type spin default %d min 50 max 150\n", engine.RFPScale)
-
-			// Razoring margins - base ±50
-			fmt.Printf("option name RazoringScale type spin default %d min 100 max 200\n", engine.RazoringScale)
-
-			// LMR (Late Move Reductions) knobs
-			fmt.Printf("option name LMRDepthLimit type spin default %d min 0 max 20\n", engine.LMRDepthLimit)
-
-			// Null-move pruning knobs
-			fmt.Printf("option name NullMoveMinDepth type spin default %d min 2 max 10\n", engine.NullMoveMinDepth)
-			fmt.Printf("option name NMMarginBase type spin default %d min 120 max 250\n", engine.NMMarginBase)
-			fmt.Printf("option name NMMarginDepth type spin default %d min 10 max 25\n", engine.NMMarginDepth)
-
-			// Additional LMP margins - base ±3
-			fmt.Printf("option name LMPOffset type spin default %d min 1 max 6\n", engine.LMPOffset)
-
-			// LMR parameters - base ±50 for history values
-			fmt.Printf("option name LMRMoveLimit type spin default %d min 1 max 5\n", engine.LMRMoveLimit)
-			fmt.Printf("option name LMRHistoryBonus type spin default %d min 450 max 550\n", engine.LMRHistoryBonus)
-			fmt.Printf("option name LMRHistoryMalus type spin default %d min -150 max -50\n", engine.LMRHistoryMalus)
-
-			// SEE pruning parameters
-			fmt.Printf("option name QuiescenceSeeMargin type spin default %d min 100 max 200\n", engine.QuiescenceSeeMargin)
-			fmt.Printf("option name ProbCutSeeMargin type spin default %d min 100 max 200\n", engine.ProbCutSeeMargin)
-
-			// Other search parameters
-			fmt.Printf("option name DeltaMargin type spin default %d min 100 max 300\n", engine.DeltaMargin)
-			fmt.Printf("option name AspirationWindowSize type spin default %d min 10 max 100\n", engine.AspirationWindowSize)
+			printUCIOptions(os.Stdout, uciOptions)
 
 			fmt.Println("uciok")
 		case "isready":
@@ -197,6 +225,8 @@ func uciLoop() {
 			var bInc = 0
 			var movesToGo = 0
 			var depthToUse = 0
+			var moveTime = 0
+			var nodeLimit = 0
 			for goScanner.Scan() {
 				nextToken := strings.ToLower(goScanner.Text())
 				switch nextToken {
@@ -262,6 +292,26 @@ func uciLoop() {
 						continue
 					}
 					depthToUse, err = strconv.Atoi(goScanner.Text())
+				case "movetime":
+					if !goScanner.Scan() {
+						fmt.Println("info string Malformed go command option movetime")
+						continue
+					}
+					moveTime, err = strconv.Atoi(goScanner.Text())
+					if err != nil {
+						fmt.Println("info string Malformed go command option; could not convert movetime")
+						moveTime = 0
+					}
+				case "nodes":
+					if !goScanner.Scan() {
+						fmt.Println("info string Malformed go command option nodes")
+						continue
+					}
+					nodeLimit, err = strconv.Atoi(goScanner.Text())
+					if err != nil {
+						fmt.Println("info string Malformed go command option; could not convert nodes")
+						nodeLimit = 0
+					}
 				default:
 					fmt.Println("info string Unknown go subcommand", nextToken)
 					continue
@@ -289,8 +339,14 @@ func uciLoop() {
 			} else {
 				depthToUse = 50
 			}
+			if moveTime > 0 {
+				engine.SearchState.SetMoveTime(moveTime)
+			}
+			if nodeLimit > 0 {
+				engine.SearchState.SetNodeLimits(0, nodeLimit)
+			}
 
-			bestMove := engine.StartSearch(&board, uint8(depthToUse), timeToUse, incToUse, movesToGo, useCustomDepth, evalOnly, moveOrderingOnly, printSearchInformation)
+			bestMove := engine.StartSearch(&board, uint8(depthToUse), timeToUse, incToUse, movesToGo, useCustomDepth, false, moveOrderingOnly, printSearchInformation)
 			fmt.Println("bestmove ", bestMove)
 
 			// Reset after search (while not incrementing time ...)
@@ -327,65 +383,17 @@ func uciLoop() {
 			}
 			for posScanner.Scan() { // for each move
 				moveStr := strings.ToLower(posScanner.Text())
-				legalMoves := board.GenerateLegalMoves()
-				var nextMove gm.Move
-				found := false
-				for _, mv := range legalMoves {
-					if mv.String() == moveStr {
-						nextMove = mv
-						found = true
-						break
-					}
-				}
-				if !found {
-					parsed, err := gm.ParseMove(moveStr)
-					if err != nil {
-						fmt.Println("info string Contingency move parsing failed")
-						continue
-					}
-					for _, mv := range legalMoves {
-						if mv.From() == parsed.From() && mv.To() == parsed.To() && mv.PromotionPieceType() == parsed.PromotionPieceType() {
-							nextMove = mv
-							found = true
-							break
-						}
-					}
-					if !found {
-						fmt.Println("info string Move", moveStr, "not found for position", board.ToFen())
-						continue
-					}
+				nextMove, err := board.ParseUCIMove(moveStr)
+				if err != nil {
+					fmt.Println("info string Move", moveStr, "not found for position", board.ToFen())
+					continue
 				}
 				board.Apply(nextMove)
 				engine.SearchState.RecordState(&board)
 			}
 		case "setoption":
-			goScanner := bufio.NewScanner(strings.NewReader(line))
-			goScanner.Split(bufio.ScanWords)
-			goScanner.Scan() // skip "setoption", we use our uciOptionSetters instead ...
-
-			for goScanner.Scan() {
-				token := strings.ToLower(goScanner.Text())
-				if token == "name" {
-					continue
-				}
-				if opt, ok := uciOptionSetters[token]; ok {
-					if val, ok := parseIntOption(goScanner, token); ok {
-						if val < opt.min || val > opt.max {
-							fmt.Printf("info string Value %d out of range [%d, %d] for %s\n", val, opt.min, opt.max, token)
-							continue
-						}
-						opt.setter(val)
-					}
-				} else {
-					if token == "value" {
-						continue
-					}
-					// Unknown option - consume "value" and the actual value to stay in sync
-					fmt.Printf("info string Unknown option: %s\n", token)
-					if goScanner.Scan() && strings.ToLower(goScanner.Text()) == "value" {
-						goScanner.Scan() // consume the value itself
-					}
-				}
+			if err := applySetOption(uciOptions, line); err != nil {
+				fmt.Printf("info string %v\n", err)
 			}
 		default:
 			fmt.Println("info string Unknown command:", line)
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"chess-engine/engine"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// uciOptionType is the UCI option type advertised in the "uci" listing.
+type uciOptionType int
+
+const (
+	optionSpin uciOptionType = iota
+	optionCheck
+	optionCombo
+	optionString
+	optionButton
+)
+
+func (t uciOptionType) String() string {
+	switch t {
+	case optionSpin:
+		return "spin"
+	case optionCheck:
+		return "check"
+	case optionCombo:
+		return "combo"
+	case optionString:
+		return "string"
+	default:
+		return "button"
+	}
+}
+
+// uciOption is one entry of the option registry. The same table drives both
+// the "uci" listing and the "setoption" parser, so advertised ranges and
+// accepted ranges cannot drift apart.
+type uciOption struct {
+	name     string // display name; matched case-insensitively
+	kind     uciOptionType
+	min, max int      // spin bounds
+	vars     []string // combo choices
+
+	value func() string // current value, printed as the default
+
+	setInt    func(int)
+	setBool   func(bool)
+	setString func(string)
+	press     func()
+}
+
+func spinOption(name string, min, max int, value func() int, set func(int)) *uciOption {
+	return &uciOption{name: name, kind: optionSpin, min: min, max: max,
+		value: func() string { return strconv.Itoa(value()) }, setInt: set}
+}
+
+func checkOption(name string, value *bool) *uciOption {
+	return &uciOption{name: name, kind: optionCheck,
+		value: func() string { return strconv.FormatBool(*value) }, setBool: func(v bool) { *value = v }}
+}
+
+func comboOption(name string, vars []string, value *string) *uciOption {
+	return &uciOption{name: name, kind: optionCombo, vars: vars,
+		value: func() string { return *value }, setString: func(v string) { *value = v }}
+}
+
+func stringOption(name string, value *string, set func(string)) *uciOption {
+	return &uciOption{name: name, kind: optionString,
+		value: func() string { return *value }, setString: set}
+}
+
+func buttonOption(name string, press func()) *uciOption {
+	return &uciOption{name: name, kind: optionButton, press: press}
+}
+
+// Listing returns the "option name ..." line for the uci handshake.
+func (o *uciOption) Listing() string {
+	switch o.kind {
+	case optionSpin:
+		return fmt.Sprintf("option name %s type spin default %s min %d max %d", o.name, o.value(), o.min, o.max)
+	case optionCombo:
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "option name %s type combo default %s", o.name, o.value())
+		for _, v := range o.vars {
+			sb.WriteString(" var ")
+			sb.WriteString(v)
+		}
+		return sb.String()
+	case optionString:
+		v := o.value()
+		if v == "" {
+			v = "<empty>"
+		}
+		return fmt.Sprintf("option name %s type string default %s", o.name, v)
+	case optionButton:
+		return fmt.Sprintf("option name %s type button", o.name)
+	default:
+		return fmt.Sprintf("option name %s type %s default %s", o.name, o.kind, o.value())
+	}
+}
+
+// Validate reports whether value is acceptable for the option without applying it.
+func (o *uciOption) Validate(value string) error {
+	switch o.kind {
+	case optionSpin:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("Malformed value for %s: %v", o.name, err)
+		}
+		if v < o.min || v > o.max {
+			return fmt.Errorf("Value %d out of range [%d, %d] for %s", v, o.min, o.max, o.name)
+		}
+	case optionCheck:
+		if !strings.EqualFold(value, "true") && !strings.EqualFold(value, "false") {
+			return fmt.Errorf("Value %q for %s must be true or false", value, o.name)
+		}
+	case optionCombo:
+		for _, v := range o.vars {
+			if strings.EqualFold(v, value) {
+				return nil
+			}
+		}
+		return fmt.Errorf("Value %q is not a choice for %s", value, o.name)
+	}
+	return nil
+}
+
+// Set validates value against the option type and applies it.
+func (o *uciOption) Set(value string) error {
+	if err := o.Validate(value); err != nil {
+		return err
+	}
+	switch o.kind {
+	case optionSpin:
+		v, _ := strconv.Atoi(value)
+		o.setInt(v)
+	case optionCheck:
+		o.setBool(strings.EqualFold(value, "true"))
+	case optionCombo:
+		for _, v := range o.vars {
+			if strings.EqualFold(v, value) {
+				o.setString(v)
+			}
+		}
+	case optionString:
+		if value == "<empty>" {
+			value = ""
+		}
+		o.setString(value)
+	case optionButton:
+		o.press()
+	}
+	return nil
+}
+
+var uciThreads = 1
+var uciPonder = false
+var uciEvalFile = ""
+var uciSyzygyPath = ""
+
+// uciOptions is the registry of every option the engine advertises.
+var uciOptions = []*uciOption{
+	spinOption("Hash", 1, 65536, func() int { return engine.TTSize }, func(v int) { engine.SearchState.ResizeHash(v) }),
+	buttonOption("Clear Hash", func() { engine.SearchState.ClearHash() }),
+	spinOption("PawnHash", 1, 1024, func() int { return engine.PawnHashSizeMB }, engine.ResizePawnHash),
+	spinOption("Threads", 1, 1, func() int { return uciThreads }, func(v int) { uciThreads = v }),
+	checkOption("Ponder", &uciPonder),
+	spinOption("Contempt", -100, 100, func() int { return int(engine.Contempt) }, func(v int) { engine.Contempt = int32(v) }),
+	checkOption("UCI_AnalyseMode", &engine.AnalyseMode),
+	stringOption("EvalFile", &uciEvalFile, func(v string) {
+		uciEvalFile = v
+		if v != "" {
+			fmt.Println("info string EvalFile recorded; the built-in evaluation does not load external files")
+		}
+	}),
+	stringOption("SyzygyPath", &uciSyzygyPath, func(v string) {
+		uciSyzygyPath = v
+		if v != "" {
+			fmt.Println("info string SyzygyPath recorded; tablebase probing is not supported")
+		}
+	}),
+
+	// --- Search / pruning parameters exposed as UCI options ---
+
+	// Futility margins (node-level) - base ±50
+	spinOption("FutilityBase", 10, 30, func() int { return int(engine.FutilityBase) }, func(v int) { engine.FutilityBase = int32(v) }),
+	spinOption("FutilityScale", 50, 150, func() int { return int(engine.FutilityScale) }, func(v int) { engine.FutilityScale = int32(v) }),
+
+	// Reverse Futility Pruning (Static Null Move) margins - base ±50
+	spinOption("RFPScale", 50, 150, func() int { return int(engine.RFPScale) }, func(v int) { engine.RFPScale = int32(v) }),
+
+	// Razoring margins - base ±50
+	spinOption("RazoringScale", 100, 200, func() int { return int(engine.RazoringScale) }, func(v int) { engine.RazoringScale = int32(v) }),
+
+	// LMR (Late Move Reductions) knobs
+	spinOption("LMRDepthLimit", 2, 20, func() int { return int(engine.LMRDepthLimit) }, func(v int) { engine.LMRDepthLimit = int8(v) }),
+
+	// Null-move pruning knobs
+	spinOption("NullMoveMinDepth", 2, 10, func() int { return int(engine.NullMoveMinDepth) }, func(v int) { engine.NullMoveMinDepth = int8(v) }),
+	spinOption("NMMarginBase", 120, 250, func() int { return int(engine.NMMarginBase) }, func(v int) { engine.NMMarginBase = int32(v) }),
+	spinOption("NMMarginDepth", 10, 25, func() int { return int(engine.NMMarginDepth) }, func(v int) { engine.NMMarginDepth = int32(v) }),
+
+	// Additional LMP margins - base ±3
+	spinOption("LMPOffset", 1, 6, func() int { return engine.LMPOffset }, func(v int) { engine.LMPOffset = v }),
+
+	// LMR parameters - base ±50 for history values
+	spinOption("LMRMoveLimit", 2, 8, func() int { return engine.LMRMoveLimit }, func(v int) { engine.LMRMoveLimit = v }),
+	spinOption("LMRHistoryBonus", 450, 550, func() int { return engine.LMRHistoryBonus }, func(v int) { engine.LMRHistoryBonus = v }),
+	spinOption("LMRHistoryMalus", -150, -50, func() int { return engine.LMRHistoryMalus }, func(v int) { engine.LMRHistoryMalus = v }),
+
+	// SEE pruning parameters
+	spinOption("QuiescenceSeeMargin", 100, 200, func() int { return engine.QuiescenceSeeMargin }, func(v int) { engine.QuiescenceSeeMargin = v }),
+	spinOption("ProbCutSeeMargin", 100, 200, func() int { return engine.ProbCutSeeMargin }, func(v int) { engine.ProbCutSeeMargin = v }),
+	spinOption("SeeCaptureMargin", 50, 200, func() int { return engine.SeeCaptureMargin }, func(v int) { engine.SeeCaptureMargin = v }),
+
+	// Other search parameters
+	spinOption("DeltaMargin", 100, 300, func() int { return int(engine.DeltaMargin) }, func(v int) { engine.DeltaMargin = int32(v) }),
+	spinOption("AspirationWindowSize", 10, 100, func() int { return int(engine.AspirationWindowSize) }, func(v int) { engine.AspirationWindowSize = int32(v) }),
+	spinOption("LazyEvalMargin", 300, 1500, func() int { return int(engine.LazyEvalMargin) }, func(v int) { engine.LazyEvalMargin = int32(v) }),
+}
+
+// findUCIOption looks up an option by name, ignoring case.
+func findUCIOption(options []*uciOption, name string) *uciOption {
+	for _, o := range options {
+		if strings.EqualFold(o.name, name) {
+			return o
+		}
+	}
+	return nil
+}
+
+// printUCIOptions writes the option listing for the uci handshake.
+func printUCIOptions(w io.Writer, options []*uciOption) {
+	for _, o := range options {
+		fmt.Fprintln(w, o.Listing())
+	}
+}
+
+// parseSetOption splits "setoption name <id> [value <x>]" into the option name
+// and value. Both may contain spaces; the keywords are matched case-insensitively.
+func parseSetOption(line string) (name string, value string, err error) {
+	tokens := strings.Fields(line)
+	if len(tokens) < 3 || !strings.EqualFold(tokens[0], "setoption") || !strings.EqualFold(tokens[1], "name") {
+		return "", "", fmt.Errorf("Malformed setoption command: %s", line)
+	}
+	valueIdx := len(tokens)
+	for i := 2; i < len(tokens); i++ {
+		if strings.EqualFold(tokens[i], "value") {
+			valueIdx = i
+			break
+		}
+	}
+	if valueIdx == 2 {
+		return "", "", fmt.Errorf("Malformed setoption command: missing option name")
+	}
+	name = strings.Join(tokens[2:valueIdx], " ")
+	if valueIdx < len(tokens) {
+		value = strings.Join(tokens[valueIdx+1:], " ")
+	}
+	return name, value, nil
+}
+
+// applySetOption parses a setoption line and applies it to the registry.
+func applySetOption(options []*uciOption, line string) error {
+	name, value, err := parseSetOption(line)
+	if err != nil {
+		return err
+	}
+	opt := findUCIOption(options, name)
+	if opt == nil {
+		return fmt.Errorf("Unknown option: %s", name)
+	}
+	return opt.Set(value)
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"chess-engine/engine"
 	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 
 	gm "chess-engine/goosemg"
@@ -14,3 +17,208 @@ func BenchmarkMain(b *testing.B) {
 	engine.SearchState.ResetForNewGame()
 	fmt.Println("bestmove ", bestmove)
 }
+
+func TestParseSetOption(t *testing.T) {
+	cases := []struct {
+		line, name, value string
+	}{
+		{"setoption name Hash value 64", "Hash", "64"},
+		{"setoption name Clear Hash", "Clear Hash", ""},
+		{"setoption NAME SyzygyPath VALUE /tb/wdl dir", "SyzygyPath", "/tb/wdl dir"},
+		{"setoption name UCI_AnalyseMode value true", "UCI_AnalyseMode", "true"},
+	}
+	for _, tc := range cases {
+		name, value, err := parseSetOption(tc.line)
+		if err != nil {
+			t.Fatalf("parseSetOption(%q): %v", tc.line, err)
+		}
+		if name != tc.name || value != tc.value {
+			t.Errorf("parseSetOption(%q) = (%q, %q), want (%q, %q)", tc.line, name, value, tc.name, tc.value)
+		}
+	}
+	for _, bad := range []string{"setoption", "setoption Hash value 1", "setoption name value 3"} {
+		if _, _, err := parseSetOption(bad); err == nil {
+			t.Errorf("parseSetOption(%q) accepted a malformed command", bad)
+		}
+	}
+}
+
+func TestSetOptionWritesMatchingParameter(t *testing.T) {
+	oldBase, oldDepth, oldMin := engine.NMMarginBase, engine.NMMarginDepth, engine.NullMoveMinDepth
+	defer func() {
+		engine.NMMarginBase, engine.NMMarginDepth, engine.NullMoveMinDepth = oldBase, oldDepth, oldMin
+	}()
+
+	for _, line := range []string{
+		"setoption name NMMarginBase value 130",
+		"setoption name nmmargindepth value 20",
+		"setoption name NullMoveMinDepth value 3",
+	} {
+		if err := applySetOption(uciOptions, line); err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+	}
+	if engine.NMMarginBase != 130 || engine.NMMarginDepth != 20 || engine.NullMoveMinDepth != 3 {
+		t.Fatalf("got NMMarginBase=%d NMMarginDepth=%d NullMoveMinDepth=%d, want 130/20/3",
+			engine.NMMarginBase, engine.NMMarginDepth, engine.NullMoveMinDepth)
+	}
+
+	if err := applySetOption(uciOptions, "setoption name NMMarginBase value 999"); err == nil {
+		t.Errorf("out-of-range value accepted")
+	}
+	if engine.NMMarginBase != 130 {
+		t.Errorf("rejected value changed NMMarginBase to %d", engine.NMMarginBase)
+	}
+	if err := applySetOption(uciOptions, "setoption name NoSuchOption value 1"); err == nil {
+		t.Errorf("unknown option accepted")
+	}
+}
+
+func TestOptionListingMatchesRegistry(t *testing.T) {
+	var buf bytes.Buffer
+	printUCIOptions(&buf, uciOptions)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(uciOptions) {
+		t.Fatalf("listing has %d lines for %d options", len(lines), len(uciOptions))
+	}
+
+	seen := map[string]bool{}
+	for i, o := range uciOptions {
+		key := strings.ToLower(o.name)
+		if seen[key] {
+			t.Errorf("option %q registered twice", o.name)
+		}
+		seen[key] = true
+
+		if !strings.HasPrefix(lines[i], "option name "+o.name+" type "+o.kind.String()) {
+			t.Errorf("listing %q does not advertise %s as %s", lines[i], o.name, o.kind)
+		}
+		if o.kind == optionSpin {
+			def, _ := strconv.Atoi(o.value())
+			if def < o.min || def > o.max {
+				t.Errorf("%s default %d outside advertised range [%d, %d]", o.name, def, o.min, o.max)
+			}
+			// The advertised bounds must be accepted by the setter.
+			for _, v := range []int{o.min, o.max} {
+				if err := o.Validate(strconv.Itoa(v)); err != nil {
+					t.Errorf("%s rejects advertised bound %d: %v", o.name, v, err)
+				}
+			}
+			if err := o.Validate(strconv.Itoa(o.max + 1)); err == nil {
+				t.Errorf("%s accepts %d above its advertised max", o.name, o.max+1)
+			}
+		}
+	}
+}
+
+func TestTypedOptions(t *testing.T) {
+	var flag bool
+	var style = "Normal"
+	var path string
+	pressed := 0
+	options := []*uciOption{
+		checkOption("Flag", &flag),
+		comboOption("Style", []string{"Solid", "Normal", "Risky"}, &style),
+		stringOption("Some Path", &path, func(v string) { path = v }),
+		buttonOption("Reset Stuff", func() { pressed++ }),
+	}
+
+	var buf bytes.Buffer
+	printUCIOptions(&buf, options)
+	want := "option name Flag type check default false\n" +
+		"option name Style type combo default Normal var Solid var Normal var Risky\n" +
+		"option name Some Path type string default <empty>\n" +
+		"option name Reset Stuff type button\n"
+	if buf.String() != want {
+		t.Fatalf("listing:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	for _, line := range []string{
+		"setoption name Flag value true",
+		"setoption name style value risky",
+		"setoption name Some Path value C:\\my dir\\file.bin",
+		"setoption name Reset Stuff",
+	} {
+		if err := applySetOption(options, line); err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+	}
+	if !flag || style != "Risky" || path != "C:\\my dir\\file.bin" || pressed != 1 {
+		t.Fatalf("got flag=%v style=%q path=%q pressed=%d", flag, style, path, pressed)
+	}
+
+	if err := applySetOption(options, "setoption name Flag value maybe"); err == nil {
+		t.Errorf("check option accepted a non-boolean value")
+	}
+	if err := applySetOption(options, "setoption name Style value Wild"); err == nil {
+		t.Errorf("combo option accepted an unknown choice")
+	}
+	if err := applySetOption(options, "setoption name Some Path value <empty>"); err != nil || path != "" {
+		t.Errorf("string option did not reset on <empty>: path=%q err=%v", path, err)
+	}
+}
+
+// Node-limited searches never consult the clock, so the bench node counts
+// and best moves under a node budget are a signature of the search itself.
+// Update these when a change is meant to alter the search.
+const goldenBenchNodeLimit = 20000
+
+var goldenNodeLimitedBench = []struct {
+	nodes    int
+	bestMove string
+}{
+	{20076, "d2d4"},
+	{20061, "e2a6"},
+	{20024, "b4f4"},
+	{20053, "c4c5"},
+	{20143, "d7c8q"},
+	{20114, "c3d5"},
+	{20327, "c3d5"},
+	{20123, "c4d5"},
+	{20079, "d3d4"},
+	{20092, "c3d5"},
+}
+
+func TestNodeLimitedBenchIsReproducible(t *testing.T) {
+	first, _ := benchSearch(50, 0, goldenBenchNodeLimit)
+	second, _ := benchSearch(50, 0, goldenBenchNodeLimit)
+	if len(first) != len(goldenNodeLimitedBench) {
+		t.Fatalf("%d bench positions, %d golden results", len(first), len(goldenNodeLimitedBench))
+	}
+	for i, r := range first {
+		if r != second[i] {
+			t.Errorf("%s: runs differ: %d nodes %s vs %d nodes %s", r.fen, r.nodes, r.bestMove, second[i].nodes, second[i].bestMove)
+		}
+		if r.nodes > goldenBenchNodeLimit+1000 {
+			t.Errorf("%s: overshot the node budget with %d nodes", r.fen, r.nodes)
+		}
+		want := goldenNodeLimitedBench[i]
+		if r.nodes != want.nodes || r.bestMove != want.bestMove {
+			t.Errorf("%s: got %d nodes %s, golden %d nodes %s", r.fen, r.nodes, r.bestMove, want.nodes, want.bestMove)
+		}
+	}
+}
+
+func TestSoftNodeLimitedBenchIsReproducible(t *testing.T) {
+	first, _ := benchSearch(50, goldenBenchNodeLimit, 0)
+	second, _ := benchSearch(50, goldenBenchNodeLimit, 0)
+	for i, r := range first {
+		if r != second[i] {
+			t.Errorf("%s: runs differ: %d nodes %s vs %d nodes %s", r.fen, r.nodes, r.bestMove, second[i].nodes, second[i].bestMove)
+		}
+		// The soft limit only stops the search between iterations
+		if r.nodes < goldenBenchNodeLimit {
+			t.Errorf("%s: stopped after %d nodes, before the soft limit", r.fen, r.nodes)
+		}
+	}
+}
+
+func TestBenchSignature(t *testing.T) {
+	if testing.Short() {
+		t.Skip("full-depth bench takes a few seconds")
+	}
+	results, _ := benchSearch(benchDepth, 0, 0)
+	if diffs := benchSignatureDiffs(results); len(diffs) > 0 {
+		t.Errorf("bench node counts changed; update benchSignature if the search change is intended:\n%s", strings.Join(diffs, "\n"))
+	}
+}